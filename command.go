@@ -0,0 +1,201 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/migration"
+	"github.com/Thospol/go-fiber/internal/core/mongodb"
+	coresql "github.com/Thospol/go-fiber/internal/core/sql"
+	"github.com/Thospol/go-fiber/internal/migrations"
+	"github.com/Thospol/go-fiber/internal/models"
+	"github.com/Thospol/go-fiber/internal/seed"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runCommand run subcommand instead of server
+func runCommand(args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(args[1:])
+
+	case "seed":
+		return runSeed(args[1:])
+	}
+
+	return fmt.Errorf("unknown command: %s", args[0])
+}
+
+// runMigrate migrate [-database postgres|mysql] [-dir internal/migrations] up [N] | down [N] | status | create <name>,
+// create accepts `-database sqlite` for sqlite migrations
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	driver := flags.String("database", migration.Postgres, "set database driver (postgres, mysql)")
+	dir := flags.String("dir", "internal/migrations", "set migrations source path for create")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		return errors.New("usage: migrate [-database postgres|mysql] up [N] | down [N] | status | create <name>")
+	}
+
+	if args[0] == "create" {
+		if len(args) < 2 {
+			return errors.New("usage: migrate create <name>")
+		}
+
+		files, err := migration.Create(filepath.Join(*dir, *driver), args[1])
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			logrus.Infof("[migrate] created %s", file)
+		}
+		return nil
+	}
+
+	db, dialect, err := openDatabase(*driver)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	source, err := fs.Sub(migrations.FS, dialect)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := migration.New(db, dialect, source)
+	if err != nil {
+		return err
+	}
+
+	steps := 0
+	if len(args) > 1 {
+		steps, err = strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid steps: %s", args[1])
+		}
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(steps)
+		logrus.Infof("[migrate] applied %d migration(s)", len(applied))
+		return err
+
+	case "down":
+		if steps == 0 {
+			steps = 1
+		}
+		reverted, err := migrator.Down(steps)
+		logrus.Infof("[migrate] reverted %d migration(s)", len(reverted))
+		return err
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown migrate command: %s", args[0])
+}
+
+// runSeed seed [-dir seeds], apply fixtures of working environment (seeds/<environment>) into enabled databases
+func runSeed(args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	dir := flags.String("dir", "seeds", "set fixtures path")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	options := seed.Options{}
+	if config.CF.SQL.PostgreSQL.Enable {
+		if err := coresql.InitConnectionPostgreSQL(config.CF.SQL.PostgreSQL); err != nil {
+			return err
+		}
+		options.Postgres = coresql.PostgreDatabase
+	}
+
+	if config.CF.SQL.MySQL.Enable {
+		if err := coresql.InitConnectionMysql(config.CF.SQL.MySQL); err != nil {
+			return err
+		}
+		options.MySQL = coresql.MysqlDatabase
+	}
+
+	if config.CF.Mongo.Enable {
+		mongoOpts := mongoOptions(config.CF.Mongo)
+		mongoOpts.Debug = false
+		err := mongodb.InitDatabase(mongoOpts)
+		if err != nil {
+			return err
+		}
+		options.Mongo = mongodb.DB()
+	}
+
+	seeder := seed.New(options)
+	if err := seeder.Register(&models.User{}, &models.APIKey{}, &models.RecoveryCode{}); err != nil {
+		return err
+	}
+
+	applied, err := seeder.Run(filepath.Join(*dir, string(config.CF.App.Environment)))
+	logrus.Infof("[seed] applied %d fixture(s)", len(applied))
+	return err
+}
+
+// openDatabase open connection of driver from config, returns dialect of migrations
+// which is sqlite when `DRIVER_NAME` of driver config is sqlite
+func openDatabase(driver string) (*sql.DB, string, error) {
+	var conf config.DatabaseConfig
+	var database func() (*sql.DB, error)
+	switch driver {
+	case migration.Postgres:
+		conf = config.CF.SQL.PostgreSQL
+		if err := coresql.InitConnectionPostgreSQL(conf); err != nil {
+			return nil, "", err
+		}
+		database = coresql.PostgreDatabase.DB
+
+	case migration.MySQL:
+		conf = config.CF.SQL.MySQL
+		if err := coresql.InitConnectionMysql(conf); err != nil {
+			return nil, "", err
+		}
+		database = coresql.MysqlDatabase.DB
+
+	default:
+		return nil, "", fmt.Errorf("unsupported driver: %s", driver)
+	}
+
+	db, err := database()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if conf.DriverName == coresql.SQLite {
+		return db, migration.SQLite, nil
+	}
+
+	return db, driver, nil
+}
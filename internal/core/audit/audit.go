@@ -0,0 +1,201 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/query"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// Create action create
+	Create = "create"
+	// Update action update
+	Update = "update"
+	// Delete action delete
+	Delete = "delete"
+
+	// ActorUser actor type user
+	ActorUser = "user"
+	// ActorService actor type service (api key)
+	ActorService = "service"
+)
+
+var (
+	store Store
+)
+
+// Store audit record storage interface
+type Store interface {
+	Save(ctx context.Context, record *Record) error
+	History(ctx context.Context, entity, entityID string, q *query.Query) ([]*Record, *query.Meta, error)
+}
+
+// TxStore store writes records through database of change,
+// so record is committed or rolled back with change
+type TxStore interface {
+	SaveTx(database *gorm.DB, record *Record) error
+}
+
+// Record audit record
+type Record struct {
+	ID        uint      `json:"id" gorm:"primary_key" bson:"-" list:"sort"`
+	ActorType string    `json:"actorType" bson:"actor_type" list:"filter"`
+	ActorID   string    `json:"actorId" bson:"actor_id" list:"filter"`
+	RequestID string    `json:"requestId" bson:"request_id" list:"filter"`
+	Entity    string    `json:"entity" gorm:"index:idx_audit_records_entity" bson:"entity"`
+	EntityID  string    `json:"entityId" gorm:"index:idx_audit_records_entity" bson:"entity_id"`
+	Action    string    `json:"action" bson:"action" list:"filter"`
+	Before    Values    `json:"before,omitempty" gorm:"type:text" bson:"before,omitempty"`
+	After     Values    `json:"after,omitempty" gorm:"type:text" bson:"after,omitempty"`
+	Changes   Changes   `json:"changes,omitempty" gorm:"type:text" bson:"changes,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"created_at" list:"sort,filter"`
+}
+
+// TableName table name of audit record
+func (Record) TableName() string {
+	return "audit_records"
+}
+
+// Change changed value of field
+type Change struct {
+	From interface{} `json:"from" bson:"from"`
+	To   interface{} `json:"to" bson:"to"`
+}
+
+// Info actor and request of change
+type Info struct {
+	ActorType string
+	ActorID   string
+	RequestID string
+}
+
+type infoKey struct{}
+
+// WithInfo context with audit info
+func WithInfo(ctx context.Context, info *Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// FromContext audit info of context
+func FromContext(ctx context.Context) *Info {
+	if ctx == nil {
+		return &Info{}
+	}
+
+	if info, ok := ctx.Value(infoKey{}).(*Info); ok {
+		return info
+	}
+
+	return &Info{}
+}
+
+// SetStore set audit store, nil is disabled audit
+func SetStore(s Store) {
+	store = s
+}
+
+// GetStore get audit store
+func GetStore() Store {
+	return store
+}
+
+// Enabled audit store is set
+func Enabled() bool {
+	return store != nil
+}
+
+// Write write audit record of change, error is logged and never returned
+// because change is already applied
+func Write(ctx context.Context, action, entity, entityID string, before, after interface{}) {
+	if store == nil {
+		return
+	}
+
+	record := newRecord(ctx, action, entity, entityID, before, after)
+	if err := store.Save(ctx, record); err != nil {
+		logrus.Errorf("[audit] save %s %s/%s error: %s", action, entity, entityID, err)
+	}
+}
+
+// WriteTx write audit record of change through database of change (transaction of request),
+// record is written in same transaction when store is TxStore, so error is returned to roll back change
+func WriteTx(database *gorm.DB, action, entity, entityID string, before, after interface{}) error {
+	if store == nil {
+		return nil
+	}
+
+	record := newRecord(database.Statement.Context, action, entity, entityID, before, after)
+	if s, ok := store.(TxStore); ok {
+		return s.SaveTx(database, record)
+	}
+
+	if err := store.Save(database.Statement.Context, record); err != nil {
+		logrus.Errorf("[audit] save %s %s/%s error: %s", action, entity, entityID, err)
+	}
+
+	return nil
+}
+
+// newRecord audit record of change with actor and request of ctx
+func newRecord(ctx context.Context, action, entity, entityID string, before, after interface{}) *Record {
+	info := FromContext(ctx)
+	record := &Record{
+		ActorType: info.ActorType,
+		ActorID:   info.ActorID,
+		RequestID: info.RequestID,
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		Before:    toMap(before),
+		After:     toMap(after),
+		CreatedAt: time.Now(),
+	}
+	if action == Update {
+		record.Changes = diff(record.Before, record.After)
+	}
+
+	return record
+}
+
+// toMap convert entity to map by json, hidden fields (`json:"-"`) are excluded
+func toMap(i interface{}) Values {
+	if i == nil || (reflect.ValueOf(i).Kind() == reflect.Ptr && reflect.ValueOf(i).IsNil()) {
+		return nil
+	}
+
+	b, err := json.Marshal(i)
+	if err != nil {
+		return nil
+	}
+
+	m := Values{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil
+	}
+
+	return m
+}
+
+// diff changed fields between before and after
+func diff(before, after Values) Changes {
+	changes := Changes{}
+	for k, v := range after {
+		if !reflect.DeepEqual(before[k], v) {
+			changes[k] = Change{From: before[k], To: v}
+		}
+	}
+
+	for k, v := range before {
+		if _, ok := after[k]; !ok {
+			changes[k] = Change{From: v}
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/Thospol/go-fiber/internal/core/query"
+
+	"github.com/sirupsen/logrus"
+)
+
+type logStore struct{}
+
+// NewLogStore new audit store writes records to log, history is not supported
+func NewLogStore() Store {
+	return &logStore{}
+}
+
+// Save log audit record
+func (s *logStore) Save(ctx context.Context, record *Record) error {
+	logrus.WithFields(logrus.Fields{
+		"actor_type": record.ActorType,
+		"actor_id":   record.ActorID,
+		"request_id": record.RequestID,
+		"entity":     record.Entity,
+		"entity_id":  record.EntityID,
+		"action":     record.Action,
+		"changes":    record.Changes,
+	}).Info("[audit] record")
+
+	return nil
+}
+
+// History not supported
+func (s *logStore) History(ctx context.Context, entity, entityID string, q *query.Query) ([]*Record, *query.Meta, error) {
+	return []*Record{}, q.Meta(0), nil
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/query"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoStore struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+// NewMongoStore new audit store on collection, each operation is bounded by timeout (query timeout of connection)
+func NewMongoStore(collection *mongo.Collection, timeout time.Duration) Store {
+	return &mongoStore{collection: collection, timeout: timeout}
+}
+
+// operationContext context of operation bounded by timeout of store
+func (s *mongoStore) operationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, s.timeout)
+}
+
+// Save save audit record
+func (s *mongoStore) Save(ctx context.Context, record *Record) error {
+	ctx, cancel := s.operationContext(ctx)
+	defer cancel()
+
+	_, err := s.collection.InsertOne(ctx, record)
+	return err
+}
+
+// History audit records of entity, latest first when no sort
+func (s *mongoStore) History(ctx context.Context, entity, entityID string, q *query.Query) ([]*Record, *query.Meta, error) {
+	records := []*Record{}
+	filter, err := q.PrimitiveM(&records)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort, err := q.PrimitiveD(&records)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if q.Sort == "" {
+		sort = primitive.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+	}
+
+	filter["entity"] = entity
+	filter["entity_id"] = entityID
+
+	ctx, cancel := s.operationContext(ctx)
+	defer cancel()
+	total, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cur, err := s.collection.Find(ctx, filter, options.Find().
+		SetSort(sort).
+		SetSkip(int64(q.Offset())).
+		SetLimit(int64(q.GetLimit())))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := cur.All(ctx, &records); err != nil {
+		return nil, nil, err
+	}
+
+	return records, q.Meta(total), nil
+}
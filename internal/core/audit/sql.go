@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/Thospol/go-fiber/internal/core/query"
+
+	"gorm.io/gorm"
+)
+
+type sqlStore struct {
+	database *gorm.DB
+}
+
+// NewSQLStore new audit store on table `audit_records` of database,
+// records of sql changes are written to table of database of change
+func NewSQLStore(database *gorm.DB) Store {
+	return &sqlStore{database: database}
+}
+
+// Save save audit record
+func (s *sqlStore) Save(ctx context.Context, record *Record) error {
+	return s.database.WithContext(ctx).Create(record).Error
+}
+
+// SaveTx save audit record in transaction of change
+func (s *sqlStore) SaveTx(database *gorm.DB, record *Record) error {
+	return database.Session(&gorm.Session{NewDB: true}).Create(record).Error
+}
+
+// History audit records of entity, latest first when no sort
+func (s *sqlStore) History(ctx context.Context, entity, entityID string, q *query.Query) ([]*Record, *query.Meta, error) {
+	records := []*Record{}
+	filter, sort, err := q.Scopes(&records)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	database := s.database.WithContext(ctx).Model(&Record{}).
+		Where("entity = ? AND entity_id = ?", entity, entityID).
+		Scopes(filter)
+
+	var total int64
+	if err := database.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	if q.Sort == "" {
+		sort = func(db *gorm.DB) *gorm.DB {
+			return db.Order("id DESC")
+		}
+	}
+
+	if err := database.Scopes(sort, q.Paginate).Find(&records).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return records, q.Meta(total), nil
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Values field values of entity, stored as json on sql
+type Values map[string]interface{}
+
+// Changes changed fields of entity, stored as json on sql
+type Changes map[string]Change
+
+// Value implements driver.Valuer
+func (v Values) Value() (driver.Value, error) {
+	return jsonValue(v)
+}
+
+// Scan implements sql.Scanner
+func (v *Values) Scan(src interface{}) error {
+	return jsonScan(src, v)
+}
+
+// Value implements driver.Valuer
+func (c Changes) Value() (driver.Value, error) {
+	return jsonValue(c)
+}
+
+// Scan implements sql.Scanner
+func (c *Changes) Scan(src interface{}) error {
+	return jsonScan(src, c)
+}
+
+func jsonValue(i interface{}) (driver.Value, error) {
+	b, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func jsonScan(src interface{}, i interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, i)
+	case string:
+		return json.Unmarshal([]byte(v), i)
+	}
+
+	return fmt.Errorf("unsupported type %T", src)
+}
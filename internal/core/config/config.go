@@ -45,14 +45,36 @@ func (env Environment) Production() bool {
 
 // DatabaseConfig database config model
 type DatabaseConfig struct {
-	Host         string `mapstructure:"HOST"`
-	Port         int    `mapstructure:"PORT"`
-	Username     string `mapstructure:"USERNAME"`
-	Password     string `mapstructure:"PASSWORD"`
-	DatabaseName string `mapstructure:"DATABASE_NAME"`
-	DriverName   string `mapstructure:"DRIVER_NAME"`
-	Timeout      string `mapstructure:"TIMEOUT"`
-	Enable       bool   `mapstructure:"ENABLE"`
+	Host         string        `mapstructure:"HOST"`
+	Port         int           `mapstructure:"PORT"`
+	Username     string        `mapstructure:"USERNAME"`
+	Password     string        `mapstructure:"PASSWORD"`
+	DatabaseName string        `mapstructure:"DATABASE_NAME"`
+	DriverName   string        `mapstructure:"DRIVER_NAME"`
+	Timeout      time.Duration `mapstructure:"TIMEOUT"`
+	QueryTimeout time.Duration `mapstructure:"QUERY_TIMEOUT"`
+	Enable       bool          `mapstructure:"ENABLE"`
+	Pool         PoolConfig    `mapstructure:"POOL"`
+
+	// AggregateTimeout timeout of mongo aggregation of all documents, default 5 minutes
+	AggregateTimeout time.Duration `mapstructure:"AGGREGATE_TIMEOUT"`
+
+	// Replicas read replicas, credentials and database name are same as primary
+	Replicas []ReplicaConfig `mapstructure:"REPLICAS"`
+}
+
+// PoolConfig connection pool config model, zero value is driver default
+type PoolConfig struct {
+	MaxOpen     int           `mapstructure:"MAX_OPEN"`
+	MaxIdle     int           `mapstructure:"MAX_IDLE"`
+	MaxLifetime time.Duration `mapstructure:"MAX_LIFETIME"`
+	MaxIdleTime time.Duration `mapstructure:"MAX_IDLE_TIME"`
+}
+
+// ReplicaConfig read replica config model
+type ReplicaConfig struct {
+	Host string `mapstructure:"HOST"`
+	Port int    `mapstructure:"PORT"`
 }
 
 // JWTExpireTimeConfig jwt expire time config model
@@ -62,6 +84,30 @@ type JWTExpireTimeConfig struct {
 	Minute time.Duration `mapstructure:"MINUTE"`
 }
 
+// Duration total duration of expire time
+func (e JWTExpireTimeConfig) Duration() time.Duration {
+	return e.Day*24*time.Hour + e.Hour*time.Hour + e.Minute*time.Minute
+}
+
+// JWTKeyConfig jwt key config model, path of pem files
+type JWTKeyConfig struct {
+	ID         string `mapstructure:"ID"`
+	PrivateKey string `mapstructure:"PRIVATE_KEY"`
+	PublicKey  string `mapstructure:"PUBLIC_KEY"`
+}
+
+// NotificationConfig notification sender config model
+type NotificationConfig struct {
+	Driver   string `mapstructure:"DRIVER"`
+	Host     string `mapstructure:"HOST"`
+	Port     int    `mapstructure:"PORT"`
+	Username string `mapstructure:"USERNAME"`
+	Password string `mapstructure:"PASSWORD"`
+	URL      string `mapstructure:"URL"`
+	APIKey   string `mapstructure:"API_KEY"`
+	From     string `mapstructure:"FROM"`
+}
+
 // Configs config models
 type Configs struct {
 	UniversalTranslator *ut.UniversalTranslator
@@ -78,13 +124,21 @@ type Configs struct {
 		ReadTimeout  time.Duration `mapstructure:"READ_TIMEOUT"`
 		WriteTimeout time.Duration `mapstructure:"WRITE_TIMEOUT"`
 		IdleTimeout  time.Duration `mapstructure:"IDLE_TIMEOUT"`
+		// RequestTimeout deadline of request context, database calls of request are cancelled after it
+		RequestTimeout time.Duration `mapstructure:"REQUEST_TIMEOUT"`
 	} `mapstructure:"HTTP_SERVER"`
 	SQL struct {
 		PostgreSQL DatabaseConfig `mapstructure:"POSTGRE_SQL"`
 		MySQL      DatabaseConfig `mapstructure:"MY_SQL"`
 	} `mapstructure:"SQL"`
-	Mongo   DatabaseConfig `mapstructure:"MONGO"`
-	Redis   DatabaseConfig `mapstructure:"REDIS"`
+	Mongo DatabaseConfig `mapstructure:"MONGO"`
+	Redis DatabaseConfig `mapstructure:"REDIS"`
+	// Databases named connections by name, names are lower case
+	Databases struct {
+		SQL   map[string]DatabaseConfig `mapstructure:"SQL"`
+		Mongo map[string]DatabaseConfig `mapstructure:"MONGO"`
+		Redis map[string]DatabaseConfig `mapstructure:"REDIS"`
+	} `mapstructure:"DATABASES"`
 	Swagger struct {
 		Title       string   `mapstructure:"TITLE"`
 		Version     string   `mapstructure:"VERSION"`
@@ -95,14 +149,98 @@ type Configs struct {
 		Enable      bool     `mapstructure:"ENABLE"`
 	} `mapstructure:"SWAGGER"`
 	JWT struct {
-		SecretKey string `mapstructure:"SECRET_KEY"`
-		Access    struct {
+		SecretKey    string         `mapstructure:"SECRET_KEY"`
+		SigningKeyID string         `mapstructure:"SIGNING_KEY_ID"`
+		Keys         []JWTKeyConfig `mapstructure:"KEYS"`
+		Access       struct {
 			JWTExpireTimeConfig `mapstructure:"EXPIRE_TIME"`
 		} `mapstructure:"ACCESS"`
 		Refresh struct {
 			JWTExpireTimeConfig `mapstructure:"EXPIRE_TIME"`
 		} `mapstructure:"REFRESH"`
 	} `mapstructure:"JWT"`
+	Authorization struct {
+		Roles map[string][]string `mapstructure:"ROLES"`
+	} `mapstructure:"AUTHORIZATION"`
+	Notification struct {
+		Email NotificationConfig `mapstructure:"EMAIL"`
+		SMS   NotificationConfig `mapstructure:"SMS"`
+	} `mapstructure:"NOTIFICATION"`
+	Password struct {
+		Algorithm  string `mapstructure:"ALGORITHM"`
+		MinLength  int    `mapstructure:"MIN_LENGTH"`
+		BcryptCost int    `mapstructure:"BCRYPT_COST"`
+		Argon2     struct {
+			Memory     uint32 `mapstructure:"MEMORY"`
+			Time       uint32 `mapstructure:"TIME"`
+			Threads    uint8  `mapstructure:"THREADS"`
+			KeyLength  uint32 `mapstructure:"KEY_LENGTH"`
+			SaltLength uint32 `mapstructure:"SALT_LENGTH"`
+		} `mapstructure:"ARGON2"`
+	} `mapstructure:"PASSWORD"`
+	Account struct {
+		ResetPasswordExpireTime     time.Duration `mapstructure:"RESET_PASSWORD_EXPIRE_TIME"`
+		VerifyEmailExpireTime       time.Duration `mapstructure:"VERIFY_EMAIL_EXPIRE_TIME"`
+		ForgotPasswordInterval      time.Duration `mapstructure:"FORGOT_PASSWORD_INTERVAL"`
+		ForgotPasswordMaxIPRequests int           `mapstructure:"FORGOT_PASSWORD_MAX_IP_REQUESTS"`
+		ForgotPasswordWindow        time.Duration `mapstructure:"FORGOT_PASSWORD_WINDOW"`
+	} `mapstructure:"ACCOUNT"`
+	TwoFactor struct {
+		Issuer        string        `mapstructure:"ISSUER"`
+		RequiredRoles []string      `mapstructure:"REQUIRED_ROLES"`
+		ExpireTime    time.Duration `mapstructure:"EXPIRE_TIME"`
+		MaxAttempts   int           `mapstructure:"MAX_ATTEMPTS"`
+		LockDuration  time.Duration `mapstructure:"LOCK_DURATION"`
+	} `mapstructure:"TWO_FACTOR"`
+	LoginProtection struct {
+		Action             string        `mapstructure:"ACTION"`
+		MaxAccountAttempts int           `mapstructure:"MAX_ACCOUNT_ATTEMPTS"`
+		MaxIPAttempts      int           `mapstructure:"MAX_IP_ATTEMPTS"`
+		Window             time.Duration `mapstructure:"WINDOW"`
+		LockDuration       time.Duration `mapstructure:"LOCK_DURATION"`
+	} `mapstructure:"LOGIN_PROTECTION"`
+	Audit struct {
+		Enable bool   `mapstructure:"ENABLE"`
+		Store  string `mapstructure:"STORE"`
+	} `mapstructure:"AUDIT"`
+	Outbox struct {
+		Enable      bool          `mapstructure:"ENABLE"`
+		Publisher   string        `mapstructure:"PUBLISHER"`
+		Interval    time.Duration `mapstructure:"INTERVAL"`
+		BatchSize   int           `mapstructure:"BATCH_SIZE"`
+		MaxAttempts int           `mapstructure:"MAX_ATTEMPTS"`
+		Backoff     time.Duration `mapstructure:"BACKOFF"`
+		Lease       time.Duration `mapstructure:"LEASE"`
+		Webhook     struct {
+			URL    string `mapstructure:"URL"`
+			Secret string `mapstructure:"SECRET"`
+		} `mapstructure:"WEBHOOK"`
+	} `mapstructure:"OUTBOX"`
+	Pagination struct {
+		CursorSecretKey string `mapstructure:"CURSOR_SECRET_KEY"`
+	} `mapstructure:"PAGINATION"`
+	OTP struct {
+		Length          int           `mapstructure:"LENGTH"`
+		ExpireTime      time.Duration `mapstructure:"EXPIRE_TIME"`
+		MaxAttempts     int           `mapstructure:"MAX_ATTEMPTS"`
+		RequestInterval time.Duration `mapstructure:"REQUEST_INTERVAL"`
+	} `mapstructure:"OTP"`
+}
+
+// TwoFactorRequired check role is required two factor authentication
+func (c Configs) TwoFactorRequired(role string) bool {
+	for _, r := range c.TwoFactor.RequiredRoles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RolePermissions permissions of role
+func (c Configs) RolePermissions(role string) []string {
+	return c.Authorization.Roles[strings.ToLower(role)]
 }
 
 // InitConfig init config
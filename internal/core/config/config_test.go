@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInitConfigReadsMySQL(t *testing.T) {
+	for _, environment := range []string{"local", "dev", "prod"} {
+		CF = &Configs{}
+		if err := InitConfig("../../../configs", environment); err != nil {
+			t.Fatalf("%s: init config: %s", environment, err)
+		}
+
+		mysql := CF.SQL.MySQL
+		if mysql.DriverName != "mysql" || mysql.Timeout != 5*time.Second || mysql.QueryTimeout != 10*time.Second {
+			t.Errorf("%s: expected mysql driver and timeouts, got %+v", environment, mysql)
+		}
+
+		if mysql.Pool.MaxOpen != 25 || mysql.Pool.MaxIdle != 10 || mysql.Pool.MaxLifetime != 30*time.Minute {
+			t.Errorf("%s: expected mysql pool, got %+v", environment, mysql.Pool)
+		}
+	}
+
+	t.Setenv("SQL_MY_SQL_POOL_MAX_OPEN", "7")
+	CF = &Configs{}
+	if err := InitConfig("../../../configs", "local"); err != nil {
+		t.Fatalf("init config: %s", err)
+	}
+
+	if CF.SQL.MySQL.Pool.MaxOpen != 7 {
+		t.Errorf("expected max open of env, got %d", CF.SQL.MySQL.Pool.MaxOpen)
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -39,6 +40,13 @@ func (rs Result) WithLocale(c *fiber.Ctx) Result {
 	return rs
 }
 
+// WithArgs format description with args
+func (rs Result) WithArgs(args ...interface{}) Result {
+	rs.Description.EN = fmt.Sprintf(rs.Description.EN, args...)
+	rs.Description.TH = fmt.Sprintf(rs.Description.TH, args...)
+	return rs
+}
+
 // Error error description
 func (rs Result) Error() string {
 	if rs.Description.Locale == "th" {
@@ -63,6 +71,14 @@ func (rs Result) HTTPStatusCode() int {
 		return http.StatusNotFound
 	case 401: // unauthorized
 		return http.StatusUnauthorized
+	case 403: // forbidden
+		return http.StatusForbidden
+	case 409: // conflict
+		return http.StatusConflict
+	case 428: // precondition required
+		return http.StatusPreconditionRequired
+	case 429: // too many requests
+		return http.StatusTooManyRequests
 	}
 
 	return http.StatusBadRequest
@@ -83,13 +99,20 @@ type ReturnResult struct {
 	InvalidAmountPassword        Result `mapstructure:"invalid_amount_password"`
 	PasswordDoesNotMatch         Result `mapstructure:"password_does_not_match"`
 	InvalidToken                 Result `mapstructure:"invalid_token"`
+	AccountTemporarilyLocked     Result `mapstructure:"account_temporarily_locked"`
+	LoginOtpRequired             Result `mapstructure:"login_otp_required"`
+	InvalidListQuery             Result `mapstructure:"invalid_list_query"`
 	Internal                     struct {
-		Success          Result `mapstructure:"success"`
-		General          Result `mapstructure:"general"`
-		BadRequest       Result `mapstructure:"bad_request"`
-		ConnectionError  Result `mapstructure:"connection_error"`
-		DatabaseNotFound Result `mapstructure:"database_not_found"`
-		Unauthorized     Result `mapstructure:"unauthorized"`
+		Success              Result `mapstructure:"success"`
+		General              Result `mapstructure:"general"`
+		BadRequest           Result `mapstructure:"bad_request"`
+		ConnectionError      Result `mapstructure:"connection_error"`
+		DatabaseNotFound     Result `mapstructure:"database_not_found"`
+		Unauthorized         Result `mapstructure:"unauthorized"`
+		Forbidden            Result `mapstructure:"forbidden"`
+		TooManyRequests      Result `mapstructure:"too_many_requests"`
+		Conflict             Result `mapstructure:"conflict"`
+		PreconditionRequired Result `mapstructure:"precondition_required"`
 	} `mapstructure:"internal"`
 }
 
@@ -1,12 +1,15 @@
 package context
 
 import (
+	gocontext "context"
 	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
 
+	"github.com/Thospol/go-fiber/internal/core/audit"
 	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/render"
 	"github.com/Thospol/go-fiber/internal/core/sql"
 	"github.com/Thospol/go-fiber/internal/models"
 
@@ -19,22 +22,35 @@ const (
 	compositeFormDepth = 3
 	// UserKey user key
 	UserKey = "user"
+	// ServiceKey service key
+	ServiceKey = "service"
 	// LangKey lang key
 	LangKey = "lang"
 	// PostgreDatabaseKey database `postgre` key
 	PostgreDatabaseKey = "postgre_database"
-	// PostgreDatabaseKey database `mysql` key
+	// MysqlDatabaseKey database `mysql` key
 	MysqlDatabaseKey = "mysql_database"
 	// UserKey parameters key
 	ParametersKey = "parameters"
+	// RequestIDKey request id key of requestid middleware
+	RequestIDKey = "requestid"
+	// StickyKey sticky primary state key
+	StickyKey = "sticky_primary"
+	// DeadlineKey context with deadline of request key
+	DeadlineKey = "request_deadline"
 )
 
 // Context custom fiber context
 type Context interface {
 	BindValue(i interface{}, validate bool) error
+	GetDatabase(name string) *gorm.DB
 	GetPostgreDatabase() *gorm.DB
 	GetMysqlDatabase() *gorm.DB
 	GetUser() (*models.UserSession, error)
+	GetService() (*models.ServiceSession, error)
+	HasPermission(permission string) bool
+	RequestContext() gocontext.Context
+	ForcePrimary()
 }
 
 type context struct {
@@ -52,7 +68,7 @@ func (c *context) BindValue(i interface{}, validate bool) error {
 	case http.MethodGet:
 		_ = c.QueryParser(i)
 
-	case http.MethodPost:
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
 		_ = c.BodyParser(i)
 	}
 
@@ -60,6 +76,16 @@ func (c *context) BindValue(i interface{}, validate bool) error {
 	c.Locals(ParametersKey, i)
 	c.trimspace(i)
 
+	if v, ok := i.(models.VersionInterface); ok {
+		if ifMatch := c.Get(fiber.HeaderIfMatch); ifMatch != "" && ifMatch != "*" {
+			version, err := render.ParseETag(ifMatch)
+			if err != nil {
+				return config.RR.Internal.BadRequest.WithLocale(c.Ctx)
+			}
+			v.SetVersion(version)
+		}
+	}
+
 	if validate {
 		err := c.validate(i)
 		if err != nil {
@@ -69,24 +95,32 @@ func (c *context) BindValue(i interface{}, validate bool) error {
 	return nil
 }
 
-// GetPostgreDatabase get connection database `postgresql`
-func (c *context) GetPostgreDatabase() *gorm.DB {
-	val := c.Locals(PostgreDatabaseKey)
-	if val == nil {
-		return sql.PostgreDatabase
+// DatabaseKey locals key of transaction of database name
+func DatabaseKey(name string) string {
+	switch name {
+	case sql.Postgres:
+		return PostgreDatabaseKey
+	case sql.MySQL:
+		return MysqlDatabaseKey
 	}
 
-	return val.(*gorm.DB)
+	return name + "_database"
 }
 
-// GetMysqlDatabase get connection database `mysql`
-func (c *context) GetMysqlDatabase() *gorm.DB {
-	val := c.Locals(MysqlDatabaseKey)
-	if val == nil {
-		return sql.MysqlDatabase
-	}
+// GetDatabase get connection database of name (postgres, mysql or name of `DATABASES.SQL` config),
+// transaction of request when it is started, nil when name is not registered
+func (c *context) GetDatabase(name string) *gorm.DB {
+	return sql.FromContext(c.RequestContext(), name)
+}
 
-	return val.(*gorm.DB)
+// GetPostgreDatabase get connection database `postgresql`, transaction of request when it is started
+func (c *context) GetPostgreDatabase() *gorm.DB {
+	return c.GetDatabase(sql.Postgres)
+}
+
+// GetMysqlDatabase get connection database `mysql`, transaction of request when it is started
+func (c *context) GetMysqlDatabase() *gorm.DB {
+	return c.GetDatabase(sql.MySQL)
 }
 
 // GetUser get user session
@@ -99,6 +133,75 @@ func (c *context) GetUser() (*models.UserSession, error) {
 	return val.(*models.UserSession), nil
 }
 
+// GetService get service session of api key caller
+func (c *context) GetService() (*models.ServiceSession, error) {
+	val := c.Locals(ServiceKey)
+	if val == nil {
+		return nil, config.RR.Internal.Unauthorized.WithLocale(c.Ctx)
+	}
+
+	return val.(*models.ServiceSession), nil
+}
+
+// HasPermission check user session has permission or service session has scope
+func (c *context) HasPermission(permission string) bool {
+	if user, ok := c.Locals(UserKey).(*models.UserSession); ok {
+		return user.HasPermission(permission)
+	}
+
+	if service, ok := c.Locals(ServiceKey).(*models.ServiceSession); ok {
+		return service.HasScope(permission)
+	}
+
+	return false
+}
+
+// RequestContext context of request with audit info (actor and request id) and transactions of request,
+// database calls through it are cancelled at deadline of request or server shutdown only,
+// not on client disconnect (fasthttp request context is not cancelled by it)
+func (c *context) RequestContext() gocontext.Context {
+	info := &audit.Info{}
+	info.RequestID, _ = c.Locals(RequestIDKey).(string)
+	if user, ok := c.Locals(UserKey).(*models.UserSession); ok {
+		info.ActorType = audit.ActorUser
+		info.ActorID = strconv.FormatUint(uint64(user.Id), 10)
+	} else if service, ok := c.Locals(ServiceKey).(*models.ServiceSession); ok {
+		info.ActorType = audit.ActorService
+		info.ActorID = strconv.FormatUint(uint64(service.APIKeyID), 10)
+	}
+
+	base, ok := c.Locals(DeadlineKey).(gocontext.Context)
+	if !ok {
+		base = c.Context()
+	}
+
+	ctx := sql.WithSticky(audit.WithInfo(base, info), c.sticky())
+	for _, name := range sql.Names() {
+		if tx, ok := c.Locals(DatabaseKey(name)).(*gorm.DB); ok {
+			ctx = sql.WithTransaction(ctx, name, tx)
+		}
+	}
+
+	return ctx
+}
+
+// ForcePrimary route following reads of request to primary database,
+// reads are also routed to primary automatically after a write of request
+func (c *context) ForcePrimary() {
+	c.sticky().Mark()
+}
+
+// sticky sticky primary state of request
+func (c *context) sticky() *sql.Sticky {
+	if s, ok := c.Locals(StickyKey).(*sql.Sticky); ok {
+		return s
+	}
+
+	s := &sql.Sticky{}
+	c.Locals(StickyKey, s)
+	return s
+}
+
 // PathParser parse path param
 func (c *context) PathParser(i interface{}, depth int) {
 	formValue := reflect.ValueOf(i)
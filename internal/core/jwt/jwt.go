@@ -1,7 +1,13 @@
 package jwt
 
 import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
 	"fmt"
+	"math/big"
+	"os"
+	"sort"
 	"time"
 
 	"github.com/Thospol/go-fiber/internal/core/config"
@@ -9,16 +15,137 @@ import (
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
+const (
+	// AccessToken access token type
+	AccessToken = "access"
+	// RefreshToken refresh token type
+	RefreshToken = "refresh"
+	// TwoFactorToken token type for second login step, it is not accepted as access token
+	TwoFactorToken = "two_factor"
+)
+
 var (
 	hmacSampleSecret []byte
+	signingKey       *key
+	keys             = map[string]*key{}
 )
 
-// LoadKey load rsa key
-func LoadKey() {
+// key signing and verification key
+type key struct {
+	id         string
+	method     jwt.SigningMethod
+	privateKey interface{}
+	publicKey  interface{}
+}
+
+// JSONWebKey json web key (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet json web key set
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// LoadKey load hmac secret or rsa/ecdsa keys from pem files
+func LoadKey() error {
 	hmacSampleSecret = []byte(config.CF.JWT.SecretKey)
+	signingKey = nil
+	keys = map[string]*key{}
+
+	for _, kc := range config.CF.JWT.Keys {
+		k, err := loadKey(kc)
+		if err != nil {
+			return err
+		}
+
+		keys[k.id] = k
+		if k.privateKey == nil {
+			continue
+		}
+
+		// first private key is signing key only when signing key id is not set
+		if k.id == config.CF.JWT.SigningKeyID || (signingKey == nil && config.CF.JWT.SigningKeyID == "") {
+			signingKey = k
+		}
+	}
+
+	if len(keys) > 0 && signingKey == nil {
+		return fmt.Errorf("jwt: no private key for signing key id '%s'", config.CF.JWT.SigningKeyID)
+	}
+
+	return nil
+}
+
+func loadKey(kc config.JWTKeyConfig) (*key, error) {
+	if kc.ID == "" {
+		return nil, fmt.Errorf("jwt: key id is required")
+	}
+
+	k := &key{id: kc.ID}
+	if kc.PrivateKey != "" {
+		data, err := os.ReadFile(kc.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+			k.privateKey, k.publicKey = privateKey, &privateKey.PublicKey
+		} else if privateKey, err := jwt.ParseECPrivateKeyFromPEM(data); err == nil {
+			k.privateKey, k.publicKey = privateKey, &privateKey.PublicKey
+		} else {
+			return nil, fmt.Errorf("jwt: key '%s' private key is not rsa or ecdsa", kc.ID)
+		}
+	}
+
+	if kc.PublicKey != "" {
+		data, err := os.ReadFile(kc.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+			k.publicKey = publicKey
+		} else if publicKey, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+			k.publicKey = publicKey
+		} else {
+			return nil, fmt.Errorf("jwt: key '%s' public key is not rsa or ecdsa", kc.ID)
+		}
+	}
+
+	switch publicKey := k.publicKey.(type) {
+	case *rsa.PublicKey:
+		k.method = jwt.SigningMethodRS256
+
+	case *ecdsa.PublicKey:
+		switch publicKey.Curve.Params().BitSize {
+		case 256:
+			k.method = jwt.SigningMethodES256
+		case 384:
+			k.method = jwt.SigningMethodES384
+		case 521:
+			k.method = jwt.SigningMethodES512
+		default:
+			return nil, fmt.Errorf("jwt: key '%s' unsupported curve", kc.ID)
+		}
+
+	default:
+		return nil, fmt.Errorf("jwt: key '%s' has no private or public key", kc.ID)
+	}
+
+	return k, nil
 }
 
-// Signed signed payload with jwt RSA2048
+// Signed signed payload with jwt, RS256/ES256 when keys are configured otherwise HS256
 func Signed(payload map[string]interface{}, exp time.Time) (string, error) {
 	dataMap := jwt.MapClaims{
 		"iat": time.Now().Unix(),
@@ -29,9 +156,15 @@ func Signed(payload map[string]interface{}, exp time.Time) (string, error) {
 		dataMap[key] = value
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, dataMap)
+	if signingKey == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, dataMap)
+		return token.SignedString(hmacSampleSecret)
+	}
+
+	token := jwt.NewWithClaims(signingKey.method, dataMap)
+	token.Header["kid"] = signingKey.id
 
-	tokenString, err := token.SignedString(hmacSampleSecret)
+	tokenString, err := token.SignedString(signingKey.privateKey)
 	if err != nil {
 		return "", err
 	}
@@ -40,12 +173,7 @@ func Signed(payload map[string]interface{}, exp time.Time) (string, error) {
 
 // Parsed parsed jwt token
 func Parsed(tokenString string, onlyValid bool) (map[string]interface{}, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
-		}
-		return hmacSampleSecret, nil
-	})
+	token, err := jwt.Parse(tokenString, keyFunc)
 	if err != nil {
 		return nil, err
 	}
@@ -60,3 +188,74 @@ func Parsed(tokenString string, onlyValid bool) (map[string]interface{}, error)
 
 	return nil, config.RR.InvalidToken
 }
+
+// keyFunc pick verification key by `kid` header
+func keyFunc(token *jwt.Token) (interface{}, error) {
+	if len(keys) == 0 {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+		}
+		return hmacSampleSecret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	k, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("Unknown key id: %v", token.Header["kid"])
+	}
+
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return k.publicKey, nil
+}
+
+// JWKS public keys as json web key set
+func JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, k := range keys {
+		jwk := JSONWebKey{
+			Kid: k.id,
+			Use: "sig",
+			Alg: k.method.Alg(),
+		}
+
+		switch publicKey := k.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = encodeBase64URL(publicKey.N.Bytes())
+			jwk.E = encodeBase64URL(big.NewInt(int64(publicKey.E)).Bytes())
+
+		case *ecdsa.PublicKey:
+			params := publicKey.Curve.Params()
+			size := (params.BitSize + 7) / 8
+			jwk.Kty = "EC"
+			jwk.Crv = params.Name
+			jwk.X = encodeBase64URL(padBytes(publicKey.X.Bytes(), size))
+			jwk.Y = encodeBase64URL(padBytes(publicKey.Y.Bytes(), size))
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+
+	return set
+}
+
+func encodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+	return padded
+}
@@ -0,0 +1,380 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Postgres driver postgres
+	Postgres = "postgres"
+	// MySQL driver mysql
+	MySQL = "mysql"
+	// SQLite driver sqlite
+	SQLite = "sqlite"
+
+	tableName     = "schema_migrations"
+	versionFormat = "20060102150405"
+	lockTimeout   = 60
+)
+
+var (
+	fileRegexp = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	lockKey    = int64(crc32.ChecksumIEEE([]byte(tableName)))
+
+	// ErrorLockTimeout error lock timeout
+	ErrorLockTimeout = errors.New("Cannot acquire migration lock")
+)
+
+// Migration migration of version
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status status of migration
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrator migrator
+type Migrator struct {
+	db         *sql.DB
+	driver     string
+	migrations []*Migration
+}
+
+// New new migrator of driver, migrations are loaded from root of fsys
+func New(db *sql.DB, driver string, fsys fs.FS) (*Migrator, error) {
+	if driver != Postgres && driver != MySQL && driver != SQLite {
+		return nil, fmt.Errorf("unsupported driver: %s", driver)
+	}
+
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		driver:     driver,
+		migrations: migrations,
+	}, nil
+}
+
+// Up apply pending migrations, steps <= 0 is all pending migrations
+func (m *Migrator) Up(steps int) ([]*Migration, error) {
+	applied := []*Migration{}
+	err := m.withLock(func(conn *sql.Conn) error {
+		versions, err := m.appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if steps > 0 && len(applied) >= steps {
+				break
+			}
+
+			if _, ok := versions[migration.Version]; ok {
+				continue
+			}
+
+			logrus.Infof("[migration] up %d_%s", migration.Version, migration.Name)
+			err := m.run(conn, migration.Up,
+				fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (%s, %s, %s)", tableName, m.placeholder(1), m.placeholder(2), m.placeholder(3)),
+				migration.Version, migration.Name, time.Now())
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down revert applied migrations, latest first, steps <= 0 is all applied migrations
+func (m *Migrator) Down(steps int) ([]*Migration, error) {
+	reverted := []*Migration{}
+	err := m.withLock(func(conn *sql.Conn) error {
+		versions, err := m.appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if steps > 0 && len(reverted) >= steps {
+				break
+			}
+
+			if _, ok := versions[migration.Version]; !ok {
+				continue
+			}
+
+			logrus.Infof("[migration] down %d_%s", migration.Version, migration.Name)
+			err := m.run(conn, migration.Down,
+				fmt.Sprintf("DELETE FROM %s WHERE version = %s", tableName, m.placeholder(1)),
+				migration.Version)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status status of all migrations
+func (m *Migrator) Status() ([]*Status, error) {
+	statuses := []*Status{}
+	err := m.withLock(func(conn *sql.Conn) error {
+		versions, err := m.appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := &Status{
+				Version: migration.Version,
+				Name:    migration.Name,
+			}
+			if appliedAt, ok := versions[migration.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// Create create empty up/down migration files of name in dir
+func Create(dir, name string) ([]string, error) {
+	name = strings.Trim(regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, errors.New("migration name is required")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	version := time.Now().UTC().Format(versionFormat)
+	files := []string{}
+	for _, direction := range []string{"up", "down"} {
+		file := filepath.Join(dir, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+		content := fmt.Sprintf("-- %s %s\n", name, direction)
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// run run migration script and record version in a transaction
+func (m *Migrator) run(conn *sql.Conn, script, record string, args ...interface{}) error {
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range split(script) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// withLock run fn on a single connection holding the advisory lock (postgres, mysql),
+// concurrent instances wait until the lock is released
+func (m *Migrator) withLock(fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch m.driver {
+	case Postgres:
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			return err
+		}
+		defer func() {
+			_, _ = conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", lockKey)
+		}()
+
+	case MySQL:
+		var locked sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", tableName, lockTimeout).Scan(&locked); err != nil {
+			return err
+		}
+		if locked.Int64 != 1 {
+			return ErrorLockTimeout
+		}
+		defer func() {
+			_, _ = conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", tableName)
+		}()
+
+	case SQLite:
+		// sqlite has no advisory lock, database is locked by write transaction of each migration
+	}
+
+	if err := m.ensureTable(conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// ensureTable create migrations table when not exists
+func (m *Migrator) ensureTable(conn *sql.Conn) error {
+	_, err := conn.ExecContext(context.Background(), fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	version BIGINT NOT NULL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at TIMESTAMP NOT NULL
+)`, tableName))
+
+	return err
+}
+
+// appliedVersions applied versions with applied time
+func (m *Migrator) appliedVersions(conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(context.Background(), fmt.Sprintf("SELECT version, applied_at FROM %s", tableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		versions[version] = appliedAt
+	}
+
+	return versions, rows.Err()
+}
+
+func (m *Migrator) placeholder(n int) string {
+	if m.driver == Postgres {
+		return "$" + strconv.Itoa(n)
+	}
+
+	return "?"
+}
+
+// load load migrations from fsys sorted by version
+func load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		matches := fileRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// split split script into statements by `;` at end of line, comments are removed
+func split(script string) []string {
+	statements := []string{}
+	current := strings.Builder{}
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
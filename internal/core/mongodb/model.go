@@ -10,9 +10,9 @@ import (
 
 // Model common mongodb model
 type Model struct {
-	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	CreatedAt time.Time          `json:"createdAt" bson:"created_at,omitempty"`
-	UpdatedAt *time.Time         `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty" list:"sort,filter"`
+	CreatedAt time.Time          `json:"createdAt" bson:"created_at,omitempty" list:"sort,filter"`
+	UpdatedAt *time.Time         `json:"updatedAt,omitempty" bson:"updated_at,omitempty" list:"sort,filter"`
 	DeletedAt *time.Time         `json:"deletedAt,omitempty" bson:"deleted_at,omitempty"`
 }
 
@@ -26,6 +26,27 @@ type ModelInterface interface {
 	GetCreatedAt() time.Time
 }
 
+// Versioned optional version field for optimistic concurrency control
+type Versioned struct {
+	Version uint `json:"version" bson:"version"`
+}
+
+// VersionInterface versioned model interface
+type VersionInterface interface {
+	GetVersion() uint
+	SetVersion(version uint)
+}
+
+// GetVersion get version
+func (v *Versioned) GetVersion() uint {
+	return v.Version
+}
+
+// SetVersion set version
+func (v *Versioned) SetVersion(version uint) {
+	v.Version = version
+}
+
 // SetID set id
 func (model *Model) SetID(id primitive.ObjectID) {
 	model.ID = id
@@ -8,6 +8,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Thospol/go-fiber/internal/core/audit"
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/query"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
@@ -45,6 +49,17 @@ type Options struct {
 	Password         string
 	Debug            bool
 	HandleNullValues []interface{}
+
+	// MaxPoolSize 0 is driver default, MinPoolSize connections are kept open
+	MaxPoolSize     uint64
+	MinPoolSize     uint64
+	MaxConnIdleTime time.Duration
+	Timeout         time.Duration
+
+	// QueryTimeout timeout of each operation of repo, default 2 seconds
+	QueryTimeout time.Duration
+	// AggregateTimeout timeout of aggregation of all documents, default 5 minutes
+	AggregateTimeout time.Duration
 }
 
 var defaultNullValues = []interface{}{
@@ -52,15 +67,51 @@ var defaultNullValues = []interface{}{
 	int(0),
 }
 
-// InitDatabase new database
+// InitDatabase new database, connection is registered as Default
 func InitDatabase(o *Options) error {
+	conn, err := Connect(Default, o)
+	if err != nil {
+		return err
+	}
+
+	db = conn.Database
+	client = conn.Client
+	queryTimeout = conn.QueryTimeout
+	aggregateTimeout = conn.AggregateTimeout
+	return nil
+}
+
+// Connect connect database of options and register it as name
+func Connect(name string, o *Options) (*Connection, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
+	conn := &Connection{Name: name, QueryTimeout: defaultQueryTimeout, AggregateTimeout: defaultAggregateTimeout}
+	if o.QueryTimeout > 0 {
+		conn.QueryTimeout = o.QueryTimeout
+	}
+	if o.AggregateTimeout > 0 {
+		conn.AggregateTimeout = o.AggregateTimeout
+	}
 	uri := fmt.Sprintf("mongodb://%s:%d", o.URL, o.Port)
 	if o.Username != "" && o.Password != "" {
 		uri = fmt.Sprintf("mongodb://%s:%s@%s:%d/%s?connect=direct", o.Username, o.Password, o.URL, o.Port, o.DatabaseName)
 	}
 	clientOptions := options.Client().ApplyURI(uri).SetRegistry(buildNullValueDecoder(append(defaultNullValues, o.HandleNullValues)...))
+	clientOptions.SetPoolMonitor(&event.PoolMonitor{Event: conn.monitorPool})
+	if o.MaxPoolSize > 0 {
+		clientOptions.SetMaxPoolSize(o.MaxPoolSize)
+	}
+	if o.MinPoolSize > 0 {
+		clientOptions.SetMinPoolSize(o.MinPoolSize)
+	}
+	if o.MaxConnIdleTime > 0 {
+		clientOptions.SetMaxConnIdleTime(o.MaxConnIdleTime)
+	}
+	if o.Timeout > 0 {
+		clientOptions.SetConnectTimeout(o.Timeout)
+		clientOptions.SetServerSelectionTimeout(o.Timeout)
+	}
+	conn.stats = PoolStats{Name: name, MaxPoolSize: o.MaxPoolSize}
 	if o.Debug {
 		clientOptions.Monitor = &event.CommandMonitor{
 			Started: func(c context.Context, e *event.CommandStartedEvent) {
@@ -70,15 +121,16 @@ func InitDatabase(o *Options) error {
 	}
 	c, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	err = c.Ping(ctx, readpref.Primary())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	db = c.Database(o.DatabaseName)
-	client = c
-	return nil
+	conn.Client = c
+	conn.Database = c.Database(o.DatabaseName)
+	register(conn)
+	return conn, nil
 }
 
 func buildNullValueDecoder(val ...interface{}) *bsoncodec.Registry {
@@ -127,11 +179,98 @@ func Client() *mongo.Client {
 type Repo struct {
 	Collection *mongo.Collection
 	Mux        sync.Mutex
+	// Timeout timeout of each operation, query timeout of default connection when zero
+	Timeout time.Duration
+	// AggregateTimeout timeout of AggregateAllByPrimitiveA, aggregate timeout of default connection when zero
+	AggregateTimeout time.Duration
+	ctx              context.Context
+	mux              *sync.Mutex
+}
+
+// WithContext new repo of collection with context, operations are cancelled with ctx
+// and audit info of context is written on changes, mutex is shared with repo
+func (r *Repo) WithContext(ctx context.Context) *Repo {
+	return &Repo{
+		Collection:       r.Collection,
+		Timeout:          r.Timeout,
+		AggregateTimeout: r.AggregateTimeout,
+		ctx:              ctx,
+		mux:              r.locker(),
+	}
+}
+
+// locker mutex of repo, repos of WithContext share mutex of original repo
+func (r *Repo) locker() *sync.Mutex {
+	if r.mux != nil {
+		return r.mux
+	}
+
+	return &r.Mux
+}
+
+// operationContext context of operation, context of repo bounded by timeout
+func (r *Repo) operationContext() (context.Context, context.CancelFunc) {
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = queryTimeout
+	}
+
+	return r.timeoutContext(timeout)
+}
+
+// aggregateContext context of aggregation of all documents, context of repo bounded by aggregate timeout
+func (r *Repo) aggregateContext() (context.Context, context.CancelFunc) {
+	timeout := r.AggregateTimeout
+	if timeout <= 0 {
+		timeout = aggregateTimeout
+	}
+
+	return r.timeoutContext(timeout)
+}
+
+// timeoutContext context of repo bounded by timeout
+func (r *Repo) timeoutContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// audit write audit record of change
+func (r *Repo) audit(action string, before, after interface{}) {
+	entity := after
+	if entity == nil {
+		entity = before
+	}
+
+	var id string
+	if m, ok := entity.(ModelInterface); ok {
+		id = m.GetID().Hex()
+	}
+
+	audit.Write(r.ctx, action, r.Collection.Name(), id, before, after)
+}
+
+// findBefore find current document of entity for audit
+func (r *Repo) findBefore(i interface{}) interface{} {
+	m, ok := i.(ModelInterface)
+	if !audit.Enabled() || !ok {
+		return nil
+	}
+
+	before := reflect.New(reflect.TypeOf(i).Elem()).Interface()
+	if err := r.FindOneByID(m.GetID().Hex(), before); err != nil {
+		return nil
+	}
+
+	return before
 }
 
 // Create create user
 func (r *Repo) Create(i interface{}) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 	if m, ok := i.(ModelInterface); ok {
 		if m.GetCreatedAt().IsZero() {
@@ -141,16 +280,20 @@ func (r *Repo) Create(i interface{}) error {
 			m.SetID(primitive.NewObjectID())
 		}
 	}
+	if v, ok := i.(VersionInterface); ok && v.GetVersion() == 0 {
+		v.SetVersion(1)
+	}
 	_, err := r.Collection.InsertOne(ctx, i)
 	if err != nil {
 		return wrapError(err)
 	}
+	r.audit(audit.Create, nil, i)
 	return nil
 }
 
 // CreateMany create many
 func (r *Repo) CreateMany(i interface{}) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 	iV := reflect.ValueOf(i)
 	ins := make([]interface{}, 0, iV.Len())
@@ -162,6 +305,9 @@ func (r *Repo) CreateMany(i interface{}) error {
 			if m.GetID().IsZero() {
 				m.SetID(primitive.NewObjectID())
 			}
+			if v, ok := m.(VersionInterface); ok && v.GetVersion() == 0 {
+				v.SetVersion(1)
+			}
 			ins = append(ins, m)
 		}
 	}
@@ -170,16 +316,66 @@ func (r *Repo) CreateMany(i interface{}) error {
 	if err != nil {
 		return wrapError(err)
 	}
+	for _, m := range ins {
+		r.audit(audit.Create, nil, m)
+	}
 	return nil
 }
 
-// Update update
+// Update update, versioned model is updated only when version is not changed
 func (r *Repo) Update(i interface{}) error {
+	before := r.findBefore(i)
+	if err := r.update(i); err != nil {
+		return err
+	}
+
+	r.audit(audit.Update, before, i)
+	return nil
+}
+
+func (r *Repo) update(i interface{}) error {
+	if v, ok := i.(VersionInterface); ok {
+		return r.updateVersion(v, i)
+	}
+
 	return r.UpdateByPrimitiveM(primitive.M{
 		"$set": i,
 	}, i)
 }
 
+func (r *Repo) updateVersion(v VersionInterface, i interface{}) error {
+	ctx, cancel := r.operationContext()
+	defer cancel()
+	var id primitive.ObjectID
+	if m, ok := i.(ModelInterface); ok {
+		m.UpdateStamp()
+		id = m.GetID()
+	}
+
+	version := v.GetVersion()
+	v.SetVersion(version + 1)
+	r.locker().Lock()
+	result, err := r.Collection.UpdateOne(ctx,
+		primitive.M{
+			"_id":     id,
+			"version": version,
+		}, primitive.M{
+			"$set": i,
+		})
+	r.locker().Unlock()
+	if err != nil {
+		v.SetVersion(version)
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		v.SetVersion(version)
+		return config.RR.Internal.Conflict
+	}
+
+	return nil
+}
+
 // UpdateWithoutTimestamp update post without timestamp
 func (r *Repo) UpdateWithoutTimestamp(i interface{}) error {
 	var id primitive.ObjectID
@@ -197,23 +393,26 @@ func (r *Repo) UpdateWithoutTimestamp(i interface{}) error {
 		"$set": i,
 	}
 
+	before := r.findBefore(i)
 	if err := r.UpdateOneByPrimitiveM(s, u); err != nil {
 		return err
 	}
 
+	r.audit(audit.Update, before, i)
 	return nil
 }
 
 // Replace replace one
 func (r *Repo) Replace(i interface{}) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 	var id primitive.ObjectID
 	if m, ok := i.(ModelInterface); ok {
 		m.UpdateStamp()
 		id = m.GetID()
 	}
-	r.Mux.Lock()
+	before := r.findBefore(i)
+	r.locker().Lock()
 	_, err := r.Collection.ReplaceOne(ctx,
 		primitive.D{
 			primitive.E{
@@ -221,25 +420,32 @@ func (r *Repo) Replace(i interface{}) error {
 				Value: id,
 			},
 		}, i)
-	r.Mux.Unlock()
+	r.locker().Unlock()
 	if err != nil {
 		return err
 	}
+	r.audit(audit.Update, before, i)
 	return nil
 }
 
 // Delete soft delete entity
 func (r *Repo) Delete(i interface{}) error {
+	before := r.findBefore(i)
 	if m, ok := i.(ModelInterface); ok {
 		m.DeleteStamp()
 	}
 
-	return r.Update(i)
+	if err := r.update(i); err != nil {
+		return err
+	}
+
+	r.audit(audit.Delete, before, nil)
+	return nil
 }
 
 // HardDelete hard delete entity
 func (r *Repo) HardDelete(i interface{}) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 	var id primitive.ObjectID
 	if m, ok := i.(ModelInterface); ok {
@@ -250,23 +456,24 @@ func (r *Repo) HardDelete(i interface{}) error {
 		"_id": id,
 	}
 
-	r.Mux.Lock()
+	r.locker().Lock()
 	_, err := r.Collection.DeleteOne(ctx, d)
-	r.Mux.Unlock()
+	r.locker().Unlock()
 	if err != nil {
 		return err
 	}
+	r.audit(audit.Delete, i, nil)
 	return nil
 }
 
 // HardDeleteAllByPrimitiveM hard delete all by primitive M
 func (r *Repo) HardDeleteAllByPrimitiveM(s primitive.M) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 
-	r.Mux.Lock()
+	r.locker().Lock()
 	_, err := r.Collection.DeleteMany(ctx, s)
-	r.Mux.Unlock()
+	r.locker().Unlock()
 
 	if err != nil {
 		return err
@@ -277,7 +484,7 @@ func (r *Repo) HardDeleteAllByPrimitiveM(s primitive.M) error {
 
 // Upsert upsert
 func (r *Repo) Upsert(i interface{}, s primitive.M) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 	var id primitive.ObjectID
 	if m, ok := i.(ModelInterface); ok {
@@ -292,12 +499,12 @@ func (r *Repo) Upsert(i interface{}, s primitive.M) error {
 			m.Stamp()
 		}
 	}
-	r.Mux.Lock()
+	r.locker().Lock()
 	_, err := r.Collection.UpdateOne(ctx,
 		s, primitive.M{
 			"$set": i,
 		}, options.Update().SetUpsert(true))
-	r.Mux.Unlock()
+	r.locker().Unlock()
 
 	if err != nil {
 		return err
@@ -307,12 +514,12 @@ func (r *Repo) Upsert(i interface{}, s primitive.M) error {
 
 // UpsertBySelectorAndUpdate upsert by selector and update
 func (r *Repo) UpsertBySelectorAndUpdate(s primitive.M, u primitive.M) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 
-	r.Mux.Lock()
+	r.locker().Lock()
 	_, err := r.Collection.UpdateOne(ctx, s, u, options.Update().SetUpsert(true))
-	r.Mux.Unlock()
+	r.locker().Unlock()
 
 	if err != nil {
 		return err
@@ -378,7 +585,7 @@ func (r *Repo) Inc(id primitive.ObjectID, path string, value int) error {
 
 // UpdateByPrimitiveM Update By Primitive M
 func (r *Repo) UpdateByPrimitiveM(m primitive.M, i interface{}) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 	var id primitive.ObjectID
 	if m, ok := i.(ModelInterface); ok {
@@ -387,7 +594,7 @@ func (r *Repo) UpdateByPrimitiveM(m primitive.M, i interface{}) error {
 	} else if oid, ok := i.(primitive.ObjectID); ok {
 		id = oid
 	}
-	r.Mux.Lock()
+	r.locker().Lock()
 	_, err := r.Collection.UpdateOne(ctx,
 		primitive.D{
 			primitive.E{
@@ -395,7 +602,7 @@ func (r *Repo) UpdateByPrimitiveM(m primitive.M, i interface{}) error {
 				Value: id,
 			},
 		}, m)
-	r.Mux.Unlock()
+	r.locker().Unlock()
 	if err != nil {
 		return err
 	}
@@ -404,12 +611,12 @@ func (r *Repo) UpdateByPrimitiveM(m primitive.M, i interface{}) error {
 
 // UpdateManyByPrimitiveM update many by primitive M
 func (r *Repo) UpdateManyByPrimitiveM(s primitive.M, u primitive.M) (*mongo.UpdateResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 
-	r.Mux.Lock()
+	r.locker().Lock()
 	result, err := r.Collection.UpdateMany(ctx, s, u)
-	r.Mux.Unlock()
+	r.locker().Unlock()
 
 	if err != nil {
 		return nil, err
@@ -420,12 +627,12 @@ func (r *Repo) UpdateManyByPrimitiveM(s primitive.M, u primitive.M) (*mongo.Upda
 
 // UpdateOneByPrimitiveM update many by primitive M
 func (r *Repo) UpdateOneByPrimitiveM(s primitive.M, u primitive.M) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 
-	r.Mux.Lock()
+	r.locker().Lock()
 	_, err := r.Collection.UpdateOne(ctx, s, u)
-	r.Mux.Unlock()
+	r.locker().Unlock()
 
 	if err != nil {
 		return err
@@ -436,7 +643,7 @@ func (r *Repo) UpdateOneByPrimitiveM(s primitive.M, u primitive.M) error {
 
 // FindOneByPrimitiveD find one by primitive.D
 func (r *Repo) FindOneByPrimitiveD(d primitive.D, i interface{}) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 	if d == nil {
 		d = primitive.D{}
@@ -450,7 +657,7 @@ func (r *Repo) FindOneByPrimitiveD(d primitive.D, i interface{}) error {
 
 // FindOneByPrimitiveM find one by primitive.M
 func (r *Repo) FindOneByPrimitiveM(m primitive.M, i interface{}, opts ...*options.FindOneOptions) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 	err := r.Collection.FindOne(ctx, m, opts...).Decode(i)
 	if err != nil {
@@ -479,7 +686,7 @@ func (r *Repo) FindOneByID(id string, i interface{}) error {
 
 // FindAll find all
 func (r *Repo) FindAll(m primitive.M, result interface{}, opts ...*options.FindOptions) (err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 	cur, err := r.Collection.Find(ctx, m, opts...)
 	if err != nil {
@@ -517,6 +724,72 @@ func (r *Repo) FindAll(m primitive.M, result interface{}, opts ...*options.FindO
 	return nil
 }
 
+// FindAllByQuery find all with filter, sort and pagination of query, selector is merged with filter
+func (r *Repo) FindAllByQuery(q *query.Query, m primitive.M, result interface{}) (*query.Meta, error) {
+	filter, err := q.PrimitiveM(result)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := q.PrimitiveD(result)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range m {
+		filter[k] = v
+	}
+
+	ctx, cancel := r.operationContext()
+	defer cancel()
+	total, err := r.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	opts := options.Find().
+		SetSkip(int64(q.Offset())).
+		SetLimit(int64(q.GetLimit()))
+	if len(sort) > 0 {
+		opts.SetSort(sort)
+	}
+
+	if err := r.FindAll(filter, result, opts); err != nil {
+		return nil, err
+	}
+
+	return q.Meta(total), nil
+}
+
+// FindAllByCursor find all with filter and keyset pagination of query, selector is merged with filter
+func (r *Repo) FindAllByCursor(q *query.Query, m primitive.M, result interface{}) (*query.CursorMeta, error) {
+	filter, err := q.PrimitiveM(result)
+	if err != nil {
+		return nil, err
+	}
+
+	keyset, err := q.Keyset(result)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range m {
+		filter[k] = v
+	}
+
+	selector := primitive.M{
+		"$and": primitive.A{filter, keyset.PrimitiveM()},
+	}
+	opts := options.Find().
+		SetSort(keyset.PrimitiveD()).
+		SetLimit(int64(keyset.Limit()))
+	if err := r.FindAll(selector, result, opts); err != nil {
+		return nil, err
+	}
+
+	return keyset.Meta(result)
+}
+
 // FindAllByIDs find all by ids
 func (r *Repo) FindAllByIDs(ids []string, i interface{}) error {
 	oid := r.ConvertStringToPrimitiveObjectIDs(ids)
@@ -535,7 +808,7 @@ func (r *Repo) FindAllByIDs(ids []string, i interface{}) error {
 
 // AggregateAllByPrimitiveA aggregate with pipeline by using primitive A
 func (r *Repo) AggregateAllByPrimitiveA(p primitive.A, result interface{}) (err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute) // TODO: Just for tester to test other issue
+	ctx, cancel := r.aggregateContext()
 	defer cancel()
 	opts := options.Aggregate()
 	cur, err := r.Collection.Aggregate(ctx, p, opts)
@@ -582,7 +855,7 @@ func (r *Repo) AggregateAllByPrimitiveA(p primitive.A, result interface{}) (err
 
 // AggregateOneByPrimitiveA aggregate one with pipeline by using primitive A
 func (r *Repo) AggregateOneByPrimitiveA(p primitive.A, result interface{}) (err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 	opts := options.Aggregate()
 	cur, err := r.Collection.Aggregate(ctx, p, opts)
@@ -617,7 +890,7 @@ func (r *Repo) AggregateOneByPrimitiveA(p primitive.A, result interface{}) (err
 
 // CountDocumentByPrimitiveM count document by primitive.M
 func (r *Repo) CountDocumentByPrimitiveM(m primitive.M) (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := r.operationContext()
 	defer cancel()
 	count, err := r.Collection.CountDocuments(ctx, m)
 	if err != nil {
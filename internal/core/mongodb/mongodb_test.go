@@ -0,0 +1,16 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithContextSharesMutex(t *testing.T) {
+	repo := &Repo{}
+	withContext := repo.WithContext(context.Background())
+	nested := withContext.WithContext(context.Background())
+
+	if withContext.locker() != repo.locker() || nested.locker() != repo.locker() {
+		t.Fatal("repos of WithContext should share mutex of original repo")
+	}
+}
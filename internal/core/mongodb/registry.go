@@ -0,0 +1,112 @@
+package mongodb
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// Default connection name of `MONGO` config
+	Default = "mongo"
+
+	defaultQueryTimeout     = 2 * time.Second
+	defaultAggregateTimeout = 5 * time.Minute
+)
+
+var (
+	connections      = map[string]*Connection{}
+	connectionsMu    sync.RWMutex
+	queryTimeout     = defaultQueryTimeout
+	aggregateTimeout = defaultAggregateTimeout
+)
+
+// Connection named connection of database
+type Connection struct {
+	Name         string
+	Client       *mongo.Client
+	Database     *mongo.Database
+	QueryTimeout time.Duration
+	// AggregateTimeout timeout of aggregation of all documents of repos
+	AggregateTimeout time.Duration
+
+	stats    PoolStats
+	statsMux sync.Mutex
+}
+
+// PoolStats stats of connection pool, counted from pool events of all servers
+type PoolStats struct {
+	Name           string
+	MaxPoolSize    uint64
+	Open           int64
+	InUse          int64
+	CheckOutFailed int64
+}
+
+// register register connection, connection of same name is replaced
+func register(conn *Connection) {
+	connectionsMu.Lock()
+	connections[conn.Name] = conn
+	connectionsMu.Unlock()
+}
+
+// Database database of name (mongo or name of `DATABASES.MONGO` config), nil when name is not registered
+func Database(name string) *mongo.Database {
+	connectionsMu.RLock()
+	defer connectionsMu.RUnlock()
+
+	if conn, ok := connections[name]; ok {
+		return conn.Database
+	}
+
+	return nil
+}
+
+// Repo repo of collection of connection, operations are bounded by query timeout of connection
+func (c *Connection) Repo(collection string) *Repo {
+	return &Repo{
+		Collection:       c.Database.Collection(collection),
+		Timeout:          c.QueryTimeout,
+		AggregateTimeout: c.AggregateTimeout,
+	}
+}
+
+// Stats stats of connection pools of all connections, sorted by name
+func Stats() []PoolStats {
+	connectionsMu.RLock()
+	defer connectionsMu.RUnlock()
+
+	stats := make([]PoolStats, 0, len(connections))
+	for _, conn := range connections {
+		conn.statsMux.Lock()
+		stats = append(stats, conn.stats)
+		conn.statsMux.Unlock()
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats
+}
+
+// monitorPool count connections of pool events
+func (c *Connection) monitorPool(e *event.PoolEvent) {
+	c.statsMux.Lock()
+	defer c.statsMux.Unlock()
+
+	switch e.Type {
+	case event.ConnectionCreated:
+		c.stats.Open++
+	case event.ConnectionClosed:
+		c.stats.Open--
+	case event.GetSucceeded:
+		c.stats.InUse++
+	case event.ConnectionReturned:
+		c.stats.InUse--
+	case event.GetFailed:
+		c.stats.CheckOutFailed++
+	}
+}
@@ -0,0 +1,67 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+)
+
+const (
+	httpTimeout = 10 * time.Second
+)
+
+type httpSender struct {
+	config config.NotificationConfig
+	client *http.Client
+}
+
+type httpSenderRequest struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+// NewHTTPSender new sender send sms via http gateway
+func NewHTTPSender(cf config.NotificationConfig) Sender {
+	return &httpSender{
+		config: cf,
+		client: &http.Client{Timeout: httpTimeout},
+	}
+}
+
+// Send post message to http gateway
+func (s *httpSender) Send(recipient string, message Message) error {
+	body, err := json.Marshal(httpSenderRequest{
+		From:    s.config.From,
+		To:      recipient,
+		Message: message.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.APIKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.APIKey))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("sms gateway response status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
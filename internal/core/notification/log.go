@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+type logSender struct {
+	channel string
+}
+
+// NewLogSender new sender write message to log
+func NewLogSender(channel string) Sender {
+	return &logSender{
+		channel: channel,
+	}
+}
+
+// Send write message to log
+func (s *logSender) Send(recipient string, message Message) error {
+	logrus.Infof("[LogSender] send %s to %s subject: %s body: %s", s.channel, recipient, message.Subject, message.Body)
+	return nil
+}
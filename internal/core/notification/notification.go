@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"fmt"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+)
+
+const (
+	// LogDriver driver write message to log, use on local
+	LogDriver = "log"
+	// SMTPDriver driver send email via smtp
+	SMTPDriver = "smtp"
+	// HTTPDriver driver send sms via http gateway
+	HTTPDriver = "http"
+)
+
+// Message notification message
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Sender sender interface
+type Sender interface {
+	Send(recipient string, message Message) error
+}
+
+// Validate check drivers of email and sms, log driver is refused on release
+// because it writes otp codes and reset links to log
+func Validate(release bool, email, sms config.NotificationConfig) error {
+	if !release {
+		return nil
+	}
+
+	if email.Driver != SMTPDriver {
+		return fmt.Errorf("email notification driver %q is not allowed on release", email.Driver)
+	}
+
+	if sms.Driver != HTTPDriver {
+		return fmt.Errorf("sms notification driver %q is not allowed on release", sms.Driver)
+	}
+
+	return nil
+}
+
+// NewEmailSender new email sender by driver config
+func NewEmailSender(cf config.NotificationConfig) Sender {
+	if cf.Driver == SMTPDriver {
+		return NewSMTPSender(cf)
+	}
+
+	return NewLogSender("email")
+}
+
+// NewSMSSender new sms sender by driver config
+func NewSMSSender(cf config.NotificationConfig) Sender {
+	if cf.Driver == HTTPDriver {
+		return NewHTTPSender(cf)
+	}
+
+	return NewLogSender("sms")
+}
@@ -0,0 +1,39 @@
+package notification
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+)
+
+type smtpSender struct {
+	config config.NotificationConfig
+}
+
+// NewSMTPSender new sender send email via smtp
+func NewSMTPSender(cf config.NotificationConfig) Sender {
+	return &smtpSender{
+		config: cf,
+	}
+}
+
+// Send send email
+func (s *smtpSender) Send(recipient string, message Message) error {
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	headers := []string{
+		fmt.Sprintf("From: %s", s.config.From),
+		fmt.Sprintf("To: %s", recipient),
+		fmt.Sprintf("Subject: %s", message.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"UTF-8\"",
+	}
+	body := fmt.Sprintf("%s\r\n\r\n%s", strings.Join(headers, "\r\n"), message.Body)
+
+	return smtp.SendMail(fmt.Sprintf("%s:%d", s.config.Host, s.config.Port), auth, s.config.From, []string{recipient}, []byte(body))
+}
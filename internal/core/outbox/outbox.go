@@ -0,0 +1,75 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// StatusPending message is waiting for delivery
+	StatusPending = "pending"
+	// StatusProcessing message is claimed by relay until next attempt at (lease)
+	StatusProcessing = "processing"
+	// StatusSent message is delivered
+	StatusSent = "sent"
+	// StatusDead message is dead-lettered after max attempts
+	StatusDead = "dead"
+)
+
+// Message event of outbox, written in transaction of change and delivered by relay
+type Message struct {
+	ID            uint       `json:"id" gorm:"primary_key"`
+	Topic         string     `json:"topic"`
+	Payload       string     `json:"payload" gorm:"type:text"`
+	Status        string     `json:"status" gorm:"index:idx_outbox_messages_due"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"lastError,omitempty" gorm:"type:text"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt" gorm:"index:idx_outbox_messages_due"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	SentAt        *time.Time `json:"sentAt,omitempty"`
+}
+
+// TableName table name of outbox message
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// Publish write event of topic to outbox through database,
+// database should be transaction of change so event is committed or rolled back with it
+func Publish(database *gorm.DB, topic string, payload interface{}) error {
+	if !config.CF.Outbox.Enable {
+		return nil
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return database.Create(&Message{
+		Topic:         topic,
+		Payload:       string(b),
+		Status:        StatusPending,
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+// Requeue move dead-lettered messages back to pending, all dead messages are requeued when ids is empty
+func Requeue(database *gorm.DB, ids ...uint) (int64, error) {
+	query := database.Model(&Message{}).Where("status = ?", StatusDead)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status":          StatusPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	})
+
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,132 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	httpTimeout = 10 * time.Second
+)
+
+// Publisher publisher delivers messages of outbox
+type Publisher interface {
+	Publish(ctx context.Context, message *Message) error
+}
+
+// Event event delivered to publisher, consumers should dedupe by id as delivery is at least once
+type Event struct {
+	ID        uint            `json:"id"`
+	Topic     string          `json:"topic"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// newEvent event of message
+func newEvent(message *Message) ([]byte, error) {
+	return json.Marshal(Event{
+		ID:        message.ID,
+		Topic:     message.Topic,
+		Payload:   json.RawMessage(message.Payload),
+		CreatedAt: message.CreatedAt,
+	})
+}
+
+type redisPublisher struct {
+	client redis.Client
+}
+
+// NewRedisPublisher new publisher appends events to redis stream of topic (field `event`),
+// entries are kept until consumers (consumer groups) read them, unlike pub/sub
+func NewRedisPublisher(client redis.Client) Publisher {
+	return &redisPublisher{client: client}
+}
+
+// Publish append event to stream of topic
+func (p *redisPublisher) Publish(ctx context.Context, message *Message) error {
+	body, err := newEvent(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.client.AddToStream(ctx, message.Topic, "event", body)
+	return err
+}
+
+type webhookPublisher struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookPublisher new publisher posts events to url, body is signed with secret when it is set
+func NewWebhookPublisher(url, secret string) Publisher {
+	return &webhookPublisher{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: httpTimeout},
+	}
+}
+
+// Publish post event to webhook
+func (p *webhookPublisher) Publish(ctx context.Context, message *Message) error {
+	body, err := newEvent(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Outbox-Topic", message.Topic)
+	req.Header.Set("X-Outbox-Message-Id", strconv.FormatUint(uint64(message.ID), 10))
+	if p.secret != "" {
+		mac := hmac.New(sha256.New, []byte(p.secret))
+		_, _ = mac.Write(body)
+		req.Header.Set("X-Outbox-Signature", fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil))))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook response status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+type logPublisher struct{}
+
+// NewLogPublisher new publisher writes events to log
+func NewLogPublisher() Publisher {
+	return &logPublisher{}
+}
+
+// Publish log event
+func (p *logPublisher) Publish(ctx context.Context, message *Message) error {
+	logrus.WithFields(logrus.Fields{
+		"id":      message.ID,
+		"topic":   message.Topic,
+		"payload": message.Payload,
+	}).Info("[outbox] publish")
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/sql"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultInterval    = 5 * time.Second
+	defaultBatchSize   = 100
+	defaultMaxAttempts = 10
+	defaultBackoff     = 10 * time.Second
+	defaultLease       = 5 * time.Minute
+	maxBackoff         = time.Hour
+)
+
+// Options options of relay, zero values are replaced with defaults
+type Options struct {
+	Interval    time.Duration
+	BatchSize   int
+	MaxAttempts int
+	Backoff     time.Duration
+	// Lease time of claimed batch, messages of relay which crashed or overran lease are claimed again after it
+	Lease time.Duration
+}
+
+// Relay relay polls pending messages of outbox and delivers them to publisher
+type Relay struct {
+	database  *gorm.DB
+	publisher Publisher
+	options   Options
+}
+
+// NewRelay new relay
+func NewRelay(database *gorm.DB, publisher Publisher, options Options) *Relay {
+	if options.Interval <= 0 {
+		options.Interval = defaultInterval
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultBatchSize
+	}
+
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultMaxAttempts
+	}
+
+	if options.Backoff <= 0 {
+		options.Backoff = defaultBackoff
+	}
+
+	if options.Lease <= 0 {
+		options.Lease = defaultLease
+	}
+
+	return &Relay{
+		database:  database,
+		publisher: publisher,
+		options:   options,
+	}
+}
+
+// Start deliver messages every interval until ctx is done
+func (r *Relay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := r.Process(ctx)
+			if err != nil {
+				logrus.Errorf("[Relay] process outbox error: %s", err)
+				break
+			}
+
+			if n < r.options.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Process deliver a batch of due messages, returns number of processed messages.
+// batch is claimed in a short transaction and published without holding locks,
+// a crash before status is written redelivers messages after lease
+func (r *Relay) Process(ctx context.Context) (int, error) {
+	messages, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, message := range messages {
+		r.deliver(ctx, message)
+
+		// status is written even when ctx is done, so published message is not redelivered
+		err := r.database.Model(message).
+			Where("status = ?", StatusProcessing).
+			Select("status", "attempts", "last_error", "next_attempt_at", "sent_at").
+			Updates(message).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(messages), nil
+}
+
+// claim lock due messages (pending or processing of expired lease) and mark them processing until lease
+func (r *Relay) claim(ctx context.Context) ([]*Message, error) {
+	messages := []*Message{}
+	err := r.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		query := tx.Where("status IN ? AND next_attempt_at <= ?", []string{StatusPending, StatusProcessing}, now).
+			Order("id").
+			Limit(r.options.BatchSize)
+		if tx.Dialector.Name() != sql.SQLite {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		if err := query.Find(&messages).Error; err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(messages))
+		for _, message := range messages {
+			message.Status = StatusProcessing
+			message.NextAttemptAt = now.Add(r.options.Lease)
+			ids = append(ids, message.ID)
+		}
+
+		return tx.Model(&Message{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":          StatusProcessing,
+			"next_attempt_at": now.Add(r.options.Lease),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// deliver publish message and set its state, failed message is retried with backoff or dead-lettered
+func (r *Relay) deliver(ctx context.Context, message *Message) {
+	now := time.Now()
+	message.Attempts++
+
+	err := r.publisher.Publish(ctx, message)
+	if err == nil {
+		message.Status = StatusSent
+		message.SentAt = &now
+		message.LastError = ""
+		return
+	}
+
+	message.LastError = err.Error()
+	if message.Attempts >= r.options.MaxAttempts {
+		message.Status = StatusDead
+		logrus.Errorf("[Relay] message %d of topic %s is dead-lettered after %d attempts error: %s", message.ID, message.Topic, message.Attempts, err)
+		return
+	}
+
+	message.Status = StatusPending
+	message.NextAttemptAt = now.Add(r.backoff(message.Attempts))
+	logrus.Warnf("[Relay] message %d of topic %s attempt %d error: %s", message.ID, message.Topic, message.Attempts, err)
+}
+
+// backoff exponential delay before next attempt, capped at max backoff
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.options.Backoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxBackoff {
+		return maxBackoff
+	}
+
+	return delay
+}
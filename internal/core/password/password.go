@@ -0,0 +1,189 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// Argon2id algorithm argon2id
+	Argon2id = "argon2id"
+	// Bcrypt algorithm bcrypt
+	Bcrypt = "bcrypt"
+
+	defaultMinLength     = 6
+	defaultArgon2Memory  = 64 * 1024
+	defaultArgon2Time    = 1
+	defaultArgon2Threads = 2
+	defaultArgon2KeyLen  = 32
+	defaultArgon2SaltLen = 16
+)
+
+var (
+	// ErrorInvalidHash error invalid hash format
+	ErrorInvalidHash = errors.New("Invalid password hash")
+)
+
+// argon2Params argon2id parameters
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	keyLen  uint32
+}
+
+// Validate validate password policy and confirm password
+func Validate(password, confirmPassword string) error {
+	minLength := config.CF.Password.MinLength
+	if minLength <= 0 {
+		minLength = defaultMinLength
+	}
+
+	if utf8.RuneCountInString(password) < minLength {
+		return config.RR.InvalidAmountPassword
+	}
+
+	if password != confirmPassword {
+		return config.RR.PasswordDoesNotMatch
+	}
+
+	return nil
+}
+
+// Hash hash password with configured algorithm
+func Hash(password string) (string, error) {
+	if config.CF.Password.Algorithm == Bcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost())
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	}
+
+	saltLength := config.CF.Password.Argon2.SaltLength
+	if saltLength == 0 {
+		saltLength = defaultArgon2SaltLen
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	p := currentArgon2Params()
+	key := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, p.keyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		p.memory,
+		p.time,
+		p.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify compare password with hash, needsRehash is true when hash
+// was created with other algorithm or parameters than configured
+func Verify(password, hash string) (ok bool, needsRehash bool, err error) {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		p, salt, key, err := decodeArgon2(hash)
+		if err != nil {
+			return false, false, err
+		}
+
+		other := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, p.keyLen)
+		if subtle.ConstantTimeCompare(key, other) != 1 {
+			return false, false, nil
+		}
+
+		return true, config.CF.Password.Algorithm == Bcrypt || p != currentArgon2Params(), nil
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false, false, err
+	}
+
+	return true, config.CF.Password.Algorithm != Bcrypt || cost != bcryptCost(), nil
+}
+
+func decodeArgon2(hash string) (argon2Params, []byte, []byte, error) {
+	p := argon2Params{}
+	values := strings.Split(hash, "$")
+	if len(values) != 6 {
+		return p, nil, nil, ErrorInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(values[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrorInvalidHash
+	}
+
+	if _, err := fmt.Sscanf(values[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return p, nil, nil, ErrorInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(values[4])
+	if err != nil {
+		return p, nil, nil, ErrorInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(values[5])
+	if err != nil {
+		return p, nil, nil, ErrorInvalidHash
+	}
+	p.keyLen = uint32(len(key))
+
+	return p, salt, key, nil
+}
+
+func currentArgon2Params() argon2Params {
+	cf := config.CF.Password.Argon2
+	p := argon2Params{
+		memory:  cf.Memory,
+		time:    cf.Time,
+		threads: cf.Threads,
+		keyLen:  cf.KeyLength,
+	}
+
+	if p.memory == 0 {
+		p.memory = defaultArgon2Memory
+	}
+	if p.time == 0 {
+		p.time = defaultArgon2Time
+	}
+	if p.threads == 0 {
+		p.threads = defaultArgon2Threads
+	}
+	if p.keyLen == 0 {
+		p.keyLen = defaultArgon2KeyLen
+	}
+
+	return p
+}
+
+func bcryptCost() int {
+	if cost := config.CF.Password.BcryptCost; cost >= bcrypt.MinCost && cost <= bcrypt.MaxCost {
+		return cost
+	}
+
+	return bcrypt.DefaultCost
+}
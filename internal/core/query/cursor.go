@@ -0,0 +1,260 @@
+package query
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// PaginationCursor cursor (keyset) pagination
+	PaginationCursor = "cursor"
+
+	tieBreakerField    = "id"
+	mongoTieBreakerKey = "_id"
+	cursorSeparator    = "."
+)
+
+// CursorMeta cursor pagination meta
+type CursorMeta struct {
+	Limit int    `json:"limit"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+}
+
+// cursor payload of cursor token
+type cursor struct {
+	Sort     string            `json:"s"`
+	Backward bool              `json:"b,omitempty"`
+	Values   []json.RawMessage `json:"v"`
+}
+
+// Keyset keyset of cursor pagination
+type Keyset struct {
+	query    *Query
+	orders   []Order
+	values   []interface{}
+	backward bool
+}
+
+// IsCursor cursor pagination is requested
+func (q *Query) IsCursor() bool {
+	return q.Cursor != "" || strings.EqualFold(q.Pagination, PaginationCursor)
+}
+
+// Keyset parse sort and cursor of model, primary key is appended as tie breaker,
+// nullable fields are not allowed as keyset condition never matches null
+func (q *Query) Keyset(model interface{}) (*Keyset, error) {
+	orders, err := q.Orders(model)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range orders {
+		if o.Field.Nullable {
+			return nil, config.RR.InvalidListQuery.WithArgs(o.Field.Name)
+		}
+	}
+
+	k := &Keyset{query: q, orders: withTieBreaker(model, orders)}
+	if q.Cursor == "" {
+		return k, nil
+	}
+
+	c, err := decodeCursor(q.Cursor)
+	if err != nil || c.Sort != q.Sort || len(c.Values) != len(k.orders) {
+		return nil, config.RR.InvalidListQuery.WithArgs("cursor")
+	}
+
+	for i, o := range k.orders {
+		value := reflect.New(o.Field.Type)
+		if err := json.Unmarshal(c.Values[i], value.Interface()); err != nil {
+			return nil, config.RR.InvalidListQuery.WithArgs("cursor")
+		}
+		k.values = append(k.values, value.Elem().Interface())
+	}
+	k.backward = c.Backward
+
+	return k, nil
+}
+
+// Scope gorm scope of keyset condition, sort and limit, one more row is fetched for detect next page
+func (k *Keyset) Scope(db *gorm.DB) *gorm.DB {
+	if len(k.values) > 0 {
+		ors := []string{}
+		vars := []interface{}{}
+		for i, o := range k.orders {
+			ands := []string{}
+			for j := 0; j < i; j++ {
+				ands = append(ands, "? = ?")
+				vars = append(vars, clause.Column{Name: k.orders[j].Field.Column}, k.values[j])
+			}
+
+			operator := ">"
+			if o.Desc != k.backward {
+				operator = "<"
+			}
+			ands = append(ands, fmt.Sprintf("? %s ?", operator))
+			vars = append(vars, clause.Column{Name: o.Field.Column}, k.values[i])
+			ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+		}
+		db = db.Where("("+strings.Join(ors, " OR ")+")", vars...)
+	}
+
+	for _, o := range k.orders {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: o.Field.Column}, Desc: o.Desc != k.backward})
+	}
+
+	return db.Limit(k.query.GetLimit() + 1)
+}
+
+// PrimitiveM mongodb selector of keyset condition
+func (k *Keyset) PrimitiveM() primitive.M {
+	if len(k.values) == 0 {
+		return primitive.M{}
+	}
+
+	ors := primitive.A{}
+	for i, o := range k.orders {
+		m := primitive.M{}
+		for j := 0; j < i; j++ {
+			m[k.orders[j].Field.BSON] = k.values[j]
+		}
+
+		operator := "$gt"
+		if o.Desc != k.backward {
+			operator = "$lt"
+		}
+		m[o.Field.BSON] = primitive.M{operator: k.values[i]}
+		ors = append(ors, m)
+	}
+
+	return primitive.M{"$or": ors}
+}
+
+// PrimitiveD mongodb sort of keyset
+func (k *Keyset) PrimitiveD() primitive.D {
+	d := primitive.D{}
+	for _, o := range k.orders {
+		direction := 1
+		if o.Desc != k.backward {
+			direction = -1
+		}
+		d = append(d, primitive.E{Key: o.Field.BSON, Value: direction})
+	}
+
+	return d
+}
+
+// Limit limit of keyset, one more row is fetched for detect next page
+func (k *Keyset) Limit() int {
+	return k.query.GetLimit() + 1
+}
+
+// Meta trim extra row, restore order of backward page and build next/prev cursors
+func (k *Keyset) Meta(sliceValue interface{}) (*CursorMeta, error) {
+	slice := reflect.ValueOf(sliceValue)
+	for slice.Kind() == reflect.Ptr {
+		slice = slice.Elem()
+	}
+
+	limit := k.query.GetLimit()
+	more := slice.Len() > limit
+	if more {
+		slice.Set(slice.Slice(0, limit))
+	}
+
+	if k.backward {
+		swap := reflect.Swapper(slice.Interface())
+		for i, j := 0, slice.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	meta := &CursorMeta{Limit: limit}
+	if slice.Len() == 0 {
+		return meta, nil
+	}
+
+	var err error
+	if (!k.backward && more) || (k.backward && len(k.values) > 0) {
+		meta.Next, err = k.encode(slice.Index(slice.Len()-1), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if (k.backward && more) || (!k.backward && len(k.values) > 0) {
+		meta.Prev, err = k.encode(slice.Index(0), true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return meta, nil
+}
+
+// encode encode sort key of item into signed cursor token
+func (k *Keyset) encode(item reflect.Value, backward bool) (string, error) {
+	for item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface {
+		item = item.Elem()
+	}
+
+	c := cursor{Sort: k.query.Sort, Backward: backward}
+	for _, o := range k.orders {
+		raw, err := json.Marshal(item.FieldByIndex(o.Field.index).Interface())
+		if err != nil {
+			return "", err
+		}
+		c.Values = append(c.Values, raw)
+	}
+
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + cursorSeparator + sign(encoded), nil
+}
+
+// decodeCursor verify signature and decode cursor token
+func decodeCursor(token string) (*cursor, error) {
+	parts := strings.SplitN(token, cursorSeparator, 2)
+	if len(parts) != 2 || !hmac.Equal([]byte(sign(parts[0])), []byte(parts[1])) {
+		return nil, fmt.Errorf("invalid cursor signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	c := &cursor{}
+	if err := json.Unmarshal(payload, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func sign(payload string) string {
+	secret := config.CF.Pagination.CursorSecretKey
+	if secret == "" {
+		secret = config.CF.JWT.SecretKey
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
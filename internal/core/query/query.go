@@ -0,0 +1,501 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	// DefaultLimit default items per page
+	DefaultLimit = 20
+	// MaxLimit maximum items per page
+	MaxLimit = 100
+
+	tagName       = "list"
+	tagSort       = "sort"
+	tagFilter     = "filter"
+	listSeparator = ","
+	opSeparator   = ":"
+	inSeparator   = "|"
+	escape        = '\\'
+
+	// likeEscape escape character of like pattern
+	likeEscape = "\\"
+)
+
+// operators supported filter operators
+var operators = map[string]string{
+	"eq":   "=",
+	"ne":   "<>",
+	"gt":   ">",
+	"gte":  ">=",
+	"lt":   "<",
+	"lte":  "<=",
+	"like": "LIKE",
+	"in":   "IN",
+}
+
+// likeReplacer escape of like pattern wildcards
+var likeReplacer = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+// Query list query model, bind from `?page=&limit=&sort=&filter=`
+//
+// sort: comma separated fields, prefix `-` for descending e.g. `-createdAt,name`
+// filter: comma separated `field:operator:value` e.g. `role:in:admin|user,name:like:tho`,
+// `,` `:` `|` and `\` in value are escaped with `\` e.g. `name:eq:Doe\, John`. `like` is for string fields only
+type Query struct {
+	Page   int    `form:"page" json:"page" query:"page" xml:"page"`
+	Limit  int    `form:"limit" json:"limit" query:"limit" xml:"limit"`
+	Sort   string `form:"sort" json:"sort" query:"sort" xml:"sort"`
+	Filter string `form:"filter" json:"filter" query:"filter" xml:"filter"`
+
+	// Pagination `offset` (default) or `cursor`
+	Pagination string `form:"pagination" json:"pagination" query:"pagination" xml:"pagination"`
+	Cursor     string `form:"cursor" json:"cursor" query:"cursor" xml:"cursor"`
+}
+
+// Meta pagination meta
+type Meta struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"totalPages"`
+}
+
+// Result list result with pagination meta
+type Result struct {
+	Data   interface{} `json:"data"`
+	Meta   *Meta       `json:"meta,omitempty"`
+	Cursor *CursorMeta `json:"cursor,omitempty"`
+}
+
+// Order sort field
+type Order struct {
+	Field *Field
+	Desc  bool
+}
+
+// Condition filter condition
+type Condition struct {
+	Field    *Field
+	Operator string
+	Value    interface{}
+}
+
+// Field listable field of model
+type Field struct {
+	Name       string
+	Column     string
+	BSON       string
+	Type       reflect.Type
+	Sortable   bool
+	Filterable bool
+	Nullable   bool
+	index      []int
+}
+
+var (
+	fieldsCache sync.Map
+)
+
+// Offset offset of page
+func (q *Query) Offset() int {
+	return (q.GetPage() - 1) * q.GetLimit()
+}
+
+// GetPage page, start with 1
+func (q *Query) GetPage() int {
+	if q.Page < 1 {
+		return 1
+	}
+
+	return q.Page
+}
+
+// GetLimit limit, default and maximum are applied
+func (q *Query) GetLimit() int {
+	if q.Limit < 1 {
+		return DefaultLimit
+	}
+
+	if q.Limit > MaxLimit {
+		return MaxLimit
+	}
+
+	return q.Limit
+}
+
+// Meta pagination meta of total
+func (q *Query) Meta(total int64) *Meta {
+	return &Meta{
+		Page:       q.GetPage(),
+		Limit:      q.GetLimit(),
+		Total:      total,
+		TotalPages: int(math.Ceil(float64(total) / float64(q.GetLimit()))),
+	}
+}
+
+// Orders parse sort of model, only fields tagged `list:"sort"` are allowed
+func (q *Query) Orders(model interface{}) ([]Order, error) {
+	fields := Fields(model)
+	orders := []Order{}
+	for _, s := range strings.Split(q.Sort, listSeparator) {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		desc := strings.HasPrefix(s, "-")
+		name := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+		field, ok := fields[name]
+		if !ok || !field.Sortable {
+			return nil, config.RR.InvalidListQuery.WithArgs(name)
+		}
+
+		orders = append(orders, Order{Field: field, Desc: desc})
+	}
+
+	return orders, nil
+}
+
+// Conditions parse filter of model, only fields tagged `list:"filter"` are allowed
+func (q *Query) Conditions(model interface{}) ([]Condition, error) {
+	fields := Fields(model)
+	conditions := []Condition{}
+	for _, f := range split(q.Filter, listSeparator, -1) {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+
+		parts := split(f, opSeparator, 3)
+		if len(parts) != 3 {
+			return nil, config.RR.InvalidListQuery.WithArgs(f)
+		}
+
+		field, ok := fields[parts[0]]
+		if !ok || !field.Filterable {
+			return nil, config.RR.InvalidListQuery.WithArgs(parts[0])
+		}
+
+		if _, ok := operators[parts[1]]; !ok {
+			return nil, config.RR.InvalidListQuery.WithArgs(parts[1])
+		}
+
+		// LOWER of non string column fails on postgresql
+		if parts[1] == "like" && field.Type.Kind() != reflect.String {
+			return nil, config.RR.InvalidListQuery.WithArgs(parts[1])
+		}
+
+		value, err := field.parseValue(parts[1], parts[2])
+		if err != nil {
+			return nil, config.RR.InvalidListQuery.WithArgs(parts[2])
+		}
+
+		conditions = append(conditions, Condition{Field: field, Operator: parts[1], Value: value})
+	}
+
+	return conditions, nil
+}
+
+// Scopes gorm scopes of filter and sort, primary key is appended to sort as tie breaker
+func (q *Query) Scopes(model interface{}) (filter func(*gorm.DB) *gorm.DB, sort func(*gorm.DB) *gorm.DB, err error) {
+	conditions, err := q.Conditions(model)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orders, err := q.Orders(model)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter = func(db *gorm.DB) *gorm.DB {
+		for _, c := range conditions {
+			column := clause.Column{Name: c.Field.Column}
+			switch c.Operator {
+			case "like":
+				db = db.Where("LOWER(?) LIKE ? ESCAPE ?", column, "%"+escapeLike(strings.ToLower(fmt.Sprint(c.Value)))+"%", likeEscape)
+			case "in":
+				db = db.Where("? IN ?", column, c.Value)
+			default:
+				db = db.Where(fmt.Sprintf("? %s ?", operators[c.Operator]), column, c.Value)
+			}
+		}
+
+		return db
+	}
+
+	orders = withTieBreaker(model, orders)
+	sort = func(db *gorm.DB) *gorm.DB {
+		for _, o := range orders {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: o.Field.Column}, Desc: o.Desc})
+		}
+
+		return db
+	}
+
+	return filter, sort, nil
+}
+
+// withTieBreaker append primary key to orders, so order of rows with equal sort values is stable
+func withTieBreaker(model interface{}, orders []Order) []Order {
+	id, ok := Fields(model)[tieBreakerField]
+	if !ok {
+		return orders
+	}
+
+	for _, o := range orders {
+		if o.Field == id {
+			return orders
+		}
+	}
+
+	return append(orders, Order{Field: id})
+}
+
+// escapeLike escape wildcards of like value, so value is matched literally like regexp.QuoteMeta of mongodb
+func escapeLike(s string) string {
+	return likeReplacer.Replace(s)
+}
+
+// Paginate gorm scope of page and limit
+func (q *Query) Paginate(db *gorm.DB) *gorm.DB {
+	return db.Offset(q.Offset()).Limit(q.GetLimit())
+}
+
+// PrimitiveM mongodb selector of filter
+func (q *Query) PrimitiveM(model interface{}) (primitive.M, error) {
+	conditions, err := q.Conditions(model)
+	if err != nil {
+		return nil, err
+	}
+
+	m := primitive.M{}
+	for _, c := range conditions {
+		selector, ok := m[c.Field.BSON].(primitive.M)
+		if !ok {
+			selector = primitive.M{}
+			m[c.Field.BSON] = selector
+		}
+
+		switch c.Operator {
+		case "like":
+			selector["$regex"] = primitive.Regex{Pattern: regexp.QuoteMeta(fmt.Sprint(c.Value)), Options: "i"}
+		default:
+			selector["$"+c.Operator] = c.Value
+		}
+	}
+
+	return m, nil
+}
+
+// PrimitiveD mongodb sort, _id is appended as tie breaker
+func (q *Query) PrimitiveD(model interface{}) (primitive.D, error) {
+	orders, err := q.Orders(model)
+	if err != nil {
+		return nil, err
+	}
+
+	d := primitive.D{}
+	for _, o := range orders {
+		direction := 1
+		if o.Desc {
+			direction = -1
+		}
+		d = append(d, primitive.E{Key: o.Field.BSON, Value: direction})
+	}
+
+	// _id is tie breaker of every collection, so order of documents with equal sort values is stable
+	for _, e := range d {
+		if e.Key == mongoTieBreakerKey {
+			return d, nil
+		}
+	}
+
+	return append(d, primitive.E{Key: mongoTieBreakerKey, Value: 1}), nil
+}
+
+// Fields listable fields of model by json name, model can be struct, pointer or slice
+func Fields(model interface{}) map[string]*Field {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	if cached, ok := fieldsCache.Load(t); ok {
+		return cached.(map[string]*Field)
+	}
+
+	fields := map[string]*Field{}
+	parseFields(t, fields, nil)
+	fieldsCache.Store(t, fields)
+
+	return fields
+}
+
+func parseFields(t reflect.Type, fields map[string]*Field, index []int) {
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	naming := schema.NamingStrategy{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		if sf.Anonymous {
+			parseFields(indirect(sf.Type), fields, fieldIndex)
+			continue
+		}
+
+		tag, ok := sf.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+
+		name := tagValue(sf.Tag.Get("json"), sf.Name)
+		column := naming.ColumnName("", sf.Name)
+		if c, ok := schema.ParseTagSetting(sf.Tag.Get("gorm"), ";")["COLUMN"]; ok {
+			column = c
+		}
+
+		field := &Field{
+			Name:     name,
+			Column:   column,
+			BSON:     tagValue(sf.Tag.Get("bson"), column),
+			Type:     indirect(sf.Type),
+			Nullable: sf.Type.Kind() == reflect.Ptr,
+			index:    fieldIndex,
+		}
+		for _, option := range strings.Split(tag, listSeparator) {
+			switch strings.TrimSpace(option) {
+			case tagSort:
+				field.Sortable = true
+			case tagFilter:
+				field.Filterable = true
+			}
+		}
+		fields[name] = field
+	}
+}
+
+// parseValue convert query value into type of field
+func (f *Field) parseValue(operator, value string) (interface{}, error) {
+	if operator == "like" {
+		return unescape(value), nil
+	}
+
+	if operator == "in" {
+		values := []interface{}{}
+		for _, v := range split(value, inSeparator, -1) {
+			parsed, err := convert(unescape(v), f.Type)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, parsed)
+		}
+
+		return values, nil
+	}
+
+	return convert(unescape(value), f.Type)
+}
+
+// split split s by separator which is not escaped, escapes are kept, n is max parts (-1 is all)
+func split(s, separator string, n int) []string {
+	parts := []string{}
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == escape {
+			i++
+			continue
+		}
+
+		if n > 0 && len(parts) == n-1 {
+			break
+		}
+
+		if strings.HasPrefix(s[i:], separator) {
+			parts = append(parts, s[start:i])
+			start = i + len(separator)
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// unescape remove escapes of value
+func unescape(s string) string {
+	if !strings.ContainsRune(s, escape) {
+		return s
+	}
+
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == escape && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+func convert(value string, t reflect.Type) (interface{}, error) {
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		if d, err := time.Parse("2006-01-02", value); err == nil {
+			return d, nil
+		}
+		return time.Parse(time.RFC3339, value)
+
+	case reflect.TypeOf(primitive.ObjectID{}):
+		return primitive.ObjectIDFromHex(value)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 10, 64)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, 64)
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	}
+
+	return value, nil
+}
+
+func tagValue(tag, fallback string) string {
+	name := strings.Split(tag, ",")[0]
+	if name == "" || name == "-" {
+		return fallback
+	}
+
+	return name
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,217 @@
+package query
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gorm.io/gorm"
+)
+
+type queryItem struct {
+	ID   uint   `json:"id" list:"sort,filter"`
+	Name string `json:"name" list:"sort,filter"`
+	Rank int    `json:"rank" list:"sort"`
+}
+
+func openQueryTestDatabase(t *testing.T, items ...*queryItem) *gorm.DB {
+	t.Helper()
+
+	database, err := gorm.Open(sqlite.Dialector{DriverName: "sqlite", DSN: filepath.Join(t.TempDir(), "query.db")}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open: %s", err)
+	}
+
+	if err := database.AutoMigrate(&queryItem{}); err != nil {
+		t.Fatalf("migrate: %s", err)
+	}
+
+	for _, item := range items {
+		if err := database.Create(item).Error; err != nil {
+			t.Fatalf("create: %s", err)
+		}
+	}
+
+	return database
+}
+
+func TestLikeMatchesWildcardsLiterally(t *testing.T) {
+	database := openQueryTestDatabase(t,
+		&queryItem{Name: "100%"},
+		&queryItem{Name: "1000"},
+		&queryItem{Name: "a_b"},
+		&queryItem{Name: "axb"},
+		&queryItem{Name: `c\d`},
+	)
+
+	for filter, expected := range map[string]string{
+		"name:like:0%":   "100%",
+		"name:like:A_B":  "a_b",
+		"name:like:%":    "100%",
+		"name:like:_":    "a_b",
+		`name:like:c\\d`: `c\d`,
+		`name:like:\\%`:  "",
+	} {
+		q := &Query{Filter: filter}
+		filterScope, _, err := q.Scopes(&queryItem{})
+		if err != nil {
+			t.Fatalf("%s: %s", filter, err)
+		}
+
+		items := []*queryItem{}
+		if err := database.Scopes(filterScope).Find(&items).Error; err != nil {
+			t.Fatalf("%s: %s", filter, err)
+		}
+
+		if expected == "" {
+			if len(items) != 0 {
+				t.Errorf("%s: expected no item, got %+v", filter, items)
+			}
+			continue
+		}
+
+		if len(items) != 1 || items[0].Name != expected {
+			t.Errorf("%s: expected %q, got %+v", filter, expected, items)
+		}
+	}
+}
+
+func TestSortAppendsTieBreaker(t *testing.T) {
+	items := []*queryItem{}
+	for i := 0; i < 5; i++ {
+		items = append(items, &queryItem{Name: "item", Rank: 1})
+	}
+	database := openQueryTestDatabase(t, items...)
+
+	for sort, expected := range map[string]string{
+		"-rank":   "ORDER BY `rank` DESC,`id`",
+		"-id":     "ORDER BY `id` DESC",
+		"":        "ORDER BY `id`",
+		"rank,id": "ORDER BY `rank`,`id`",
+	} {
+		q := &Query{Sort: sort, Limit: 2}
+		_, sortScope, err := q.Scopes(&queryItem{})
+		if err != nil {
+			t.Fatalf("%s: %s", sort, err)
+		}
+
+		statement := database.Session(&gorm.Session{DryRun: true}).Scopes(sortScope, q.Paginate).Find(&[]*queryItem{}).Statement
+		if sql := statement.SQL.String(); !strings.Contains(sql, expected) {
+			t.Errorf("%s: expected %s, got %s", sort, expected, sql)
+		}
+	}
+
+	seen := map[uint]bool{}
+	for page := 1; page <= 3; page++ {
+		q := &Query{Sort: "rank", Page: page, Limit: 2}
+		_, sortScope, err := q.Scopes(&queryItem{})
+		if err != nil {
+			t.Fatalf("page %d: %s", page, err)
+		}
+
+		items := []*queryItem{}
+		if err := database.Scopes(sortScope, q.Paginate).Find(&items).Error; err != nil {
+			t.Fatalf("page %d: %s", page, err)
+		}
+
+		for _, item := range items {
+			if seen[item.ID] {
+				t.Fatalf("page %d: item %d is on previous page", page, item.ID)
+			}
+			seen[item.ID] = true
+		}
+	}
+
+	if len(seen) != 5 {
+		t.Fatalf("expected 5 items on pages, got %d", len(seen))
+	}
+}
+
+func TestPrimitiveDAppendsTieBreaker(t *testing.T) {
+	for sort, expected := range map[string]primitive.D{
+		"-rank": {{Key: "rank", Value: -1}, {Key: "_id", Value: 1}},
+		"-id":   {{Key: "id", Value: -1}, {Key: "_id", Value: 1}},
+		"":      {{Key: "_id", Value: 1}},
+	} {
+		d, err := (&Query{Sort: sort}).PrimitiveD(&queryItem{})
+		if err != nil {
+			t.Fatalf("%s: %s", sort, err)
+		}
+
+		if !reflect.DeepEqual(d, expected) {
+			t.Errorf("%s: expected %v, got %v", sort, expected, d)
+		}
+	}
+}
+
+func TestKeysetWalksIssuedCursors(t *testing.T) {
+	database := openQueryTestDatabase(t,
+		&queryItem{Name: "a", Rank: 2},
+		&queryItem{Name: "b", Rank: 1},
+		&queryItem{Name: "c", Rank: 2},
+		&queryItem{Name: "d", Rank: 1},
+		&queryItem{Name: "e", Rank: 3},
+		&queryItem{Name: "f", Rank: 2},
+	)
+
+	list := func(sort, token string) ([]string, *CursorMeta) {
+		t.Helper()
+
+		q := &Query{Sort: sort, Cursor: token, Pagination: PaginationCursor, Limit: 2}
+		k, err := q.Keyset(&queryItem{})
+		if err != nil {
+			t.Fatalf("%s: keyset: %s", sort, err)
+		}
+
+		items := []*queryItem{}
+		if err := database.Scopes(k.Scope).Find(&items).Error; err != nil {
+			t.Fatalf("%s: find: %s", sort, err)
+		}
+
+		meta, err := k.Meta(&items)
+		if err != nil {
+			t.Fatalf("%s: meta: %s", sort, err)
+		}
+
+		names := []string{}
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+
+		return names, meta
+	}
+
+	for sort, pages := range map[string][][]string{
+		"":      {{"a", "b"}, {"c", "d"}, {"e", "f"}},
+		"rank":  {{"b", "d"}, {"a", "c"}, {"f", "e"}},
+		"-rank": {{"e", "a"}, {"c", "f"}, {"b", "d"}},
+	} {
+		names, meta := list(sort, "")
+		if !reflect.DeepEqual(names, pages[0]) || meta.Prev != "" {
+			t.Fatalf("%s: page 1: expected %v without prev, got %v %+v", sort, pages[0], names, meta)
+		}
+
+		names, meta = list(sort, meta.Next)
+		if !reflect.DeepEqual(names, pages[1]) {
+			t.Fatalf("%s: page 2: expected %v, got %v", sort, pages[1], names)
+		}
+
+		names, last := list(sort, meta.Next)
+		if !reflect.DeepEqual(names, pages[2]) || last.Next != "" {
+			t.Fatalf("%s: page 3: expected %v without next, got %v %+v", sort, pages[2], names, last)
+		}
+
+		names, meta = list(sort, last.Prev)
+		if !reflect.DeepEqual(names, pages[1]) {
+			t.Fatalf("%s: back to page 2: expected %v, got %v", sort, pages[1], names)
+		}
+
+		names, _ = list(sort, meta.Prev)
+		if !reflect.DeepEqual(names, pages[0]) {
+			t.Fatalf("%s: back to page 1: expected %v, got %v", sort, pages[0], names)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
 	"fmt"
 	"net/http"
@@ -18,15 +19,47 @@ const (
 
 var (
 	c = &client{}
+
+	// ErrNil key does not exist
+	ErrNil = redis.ErrNil
 )
 
+// incrScript increment key and set expiry when key is created in one command,
+// so key never stays without expiry
+const incrScript = `local v = redis.call('INCR', KEYS[1])
+if v == 1 and tonumber(ARGV[1]) > 0 then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+return v`
+
+// getDeleteScript get and delete key atomically, GETDEL is not available before redis 6.2
+const getDeleteScript = `local v = redis.call('GET', KEYS[1])
+if v then redis.call('DEL', KEYS[1]) end
+return v`
+
+// addToSetScript add member to set and refresh expiry of set in one command,
+// so set never stays without expiry
+const addToSetScript = `redis.call('SADD', KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 then redis.call('PEXPIRE', KEYS[1], ARGV[2]) end
+return 1`
+
+// compareDeleteScript delete key when value of key is equal to value atomically
+const compareDeleteScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then return redis.call('DEL', KEYS[1]) end
+return 0`
+
 // Client regis client interface
 type Client interface {
-	Ping() error
-	Get(key string, value interface{}) error
-	GetKeys(pattern string) ([]string, error)
-	Set(key string, value interface{}, expiredTime time.Duration) error
-	Delete(key string) error
+	Ping(ctx context.Context) error
+	Get(ctx context.Context, key string, value interface{}) error
+	GetInt(ctx context.Context, key string) (int, error)
+	GetDelete(ctx context.Context, key string, value interface{}) error
+	GetKeys(ctx context.Context, pattern string) ([]string, error)
+	Set(ctx context.Context, key string, value interface{}, expiredTime time.Duration) error
+	Delete(ctx context.Context, key string) error
+	CompareAndDelete(ctx context.Context, key string, value interface{}) (bool, error)
+	Incr(ctx context.Context, key string, expiredTime time.Duration) (int, error)
+	AddToSet(ctx context.Context, key string, member string, expiredTime time.Duration) error
+	GetSetMembers(ctx context.Context, key string) ([]string, error)
+	RemoveFromSet(ctx context.Context, key string, member string) error
+	AddToStream(ctx context.Context, stream string, field string, value []byte) (string, error)
 	Close()
 	MapRedisKey(r *http.Request, data interface{}, prefixKey string) string
 }
@@ -36,30 +69,67 @@ type Configuration struct {
 	Host     string
 	Port     int
 	Password string
+
+	// MaxIdle default 3, MaxActive 0 is unlimited, when MaxActive is set Get waits for free connection
+	MaxIdle         int
+	MaxActive       int
+	IdleTimeout     time.Duration
+	MaxConnLifetime time.Duration
+	Timeout         time.Duration
+
+	// QueryTimeout timeout of each command, commands are also bounded by deadline of context
+	QueryTimeout time.Duration
 }
 
-// Init start redis connection
+// Init start redis connection, connection is registered as Default
 func Init(config Configuration) error {
+	conn, err := Open(Default, config)
+	if err != nil {
+		return err
+	}
+
+	c = conn.(*client)
+	return nil
+}
+
+// Open start redis connection and register it as name
+func Open(name string, config Configuration) (Client, error) {
+	if config.MaxIdle <= 0 {
+		config.MaxIdle = redisMaxIdle
+	}
+
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = redisIdleTime
+	}
+
 	pool := &redis.Pool{
-		MaxIdle:     redisMaxIdle,
-		IdleTimeout: redisIdleTime,
+		MaxIdle:         config.MaxIdle,
+		MaxActive:       config.MaxActive,
+		IdleTimeout:     config.IdleTimeout,
+		MaxConnLifetime: config.MaxConnLifetime,
+		Wait:            config.MaxActive > 0,
 		Dial: func() (redis.Conn, error) {
 			return redis.Dial("tcp", fmt.Sprintf("%s:%d", config.Host, config.Port),
 				redis.DialPassword(config.Password),
+				redis.DialConnectTimeout(config.Timeout),
+				redis.DialReadTimeout(config.Timeout),
+				redis.DialWriteTimeout(config.Timeout),
 			)
 		},
 	}
 
-	c = &client{
-		pool: pool,
+	conn := &client{
+		pool:    pool,
+		timeout: config.QueryTimeout,
 	}
 
-	err := c.Ping()
+	err := conn.Ping(context.Background())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	register(name, conn)
+	return conn, nil
 }
 
 // GetConnection get client connection
@@ -69,12 +139,59 @@ func GetConnection() Client {
 
 // Client redis cache
 type client struct {
-	pool *redis.Pool
+	pool    *redis.Pool
+	timeout time.Duration
+}
+
+// conn connection of pool for ctx, ctx is bounded by query timeout of client
+func (cache *client) conn(ctx context.Context) *conn {
+	cancel := context.CancelFunc(func() {})
+	if cache.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, cache.timeout)
+	}
+
+	c, err := cache.pool.GetContext(ctx)
+	return &conn{conn: c, ctx: ctx, cancel: cancel, err: err}
+}
+
+// conn connection of context, error of getting connection is returned by Do like connection of Pool.Get
+type conn struct {
+	conn   redis.Conn
+	ctx    context.Context
+	cancel context.CancelFunc
+	err    error
+}
+
+// Do send command with remaining time of context as read timeout
+func (c *conn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	if err := c.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := c.ctx.Deadline(); ok {
+		return redis.DoWithTimeout(c.conn, time.Until(deadline), cmd, args...)
+	}
+
+	return c.conn.Do(cmd, args...)
+}
+
+// Close return connection to pool
+func (c *conn) Close() error {
+	c.cancel()
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
 }
 
 // Ping ping servier
-func (cache *client) Ping() error {
-	conn := cache.pool.Get()
+func (cache *client) Ping(ctx context.Context) error {
+	conn := cache.conn(ctx)
 	defer func() {
 		_ = conn.Close()
 	}()
@@ -87,8 +204,8 @@ func (cache *client) Ping() error {
 }
 
 // Get get value from key
-func (cache *client) Get(key string, value interface{}) error {
-	conn := cache.pool.Get()
+func (cache *client) Get(ctx context.Context, key string, value interface{}) error {
+	conn := cache.conn(ctx)
 	defer func() {
 		_ = conn.Close()
 	}()
@@ -108,9 +225,33 @@ func (cache *client) Get(key string, value interface{}) error {
 	return nil
 }
 
+// GetInt get integer value from key, value of Incr is not gob encoded so it is read by GetInt
+func (cache *client) GetInt(ctx context.Context, key string) (int, error) {
+	conn := cache.conn(ctx)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	return redis.Int(conn.Do("GET", key))
+}
+
+// GetDelete get value from key and delete key atomically, redis.ErrNil when key does not exist
+func (cache *client) GetDelete(ctx context.Context, key string, value interface{}) error {
+	conn := cache.conn(ctx)
+	defer func() {
+		_ = conn.Close()
+	}()
+	str, err := redis.String(conn.Do("EVAL", getDeleteScript, 1, key))
+	if err != nil {
+		return err
+	}
+
+	return gob.NewDecoder(bytes.NewBufferString(str)).Decode(value)
+}
+
 // GetKeys get keys
-func (cache *client) GetKeys(pattern string) ([]string, error) {
-	conn := cache.pool.Get()
+func (cache *client) GetKeys(ctx context.Context, pattern string) ([]string, error) {
+	conn := cache.conn(ctx)
 	defer func() {
 		_ = conn.Close()
 	}()
@@ -135,9 +276,9 @@ func (cache *client) GetKeys(pattern string) ([]string, error) {
 	return keys, nil
 }
 
-// Set set value to key
-func (cache *client) Set(key string, value interface{}, expiredTime time.Duration) error {
-	conn := cache.pool.Get()
+// Set set value to key, key does not expire when expired time is less than a millisecond
+func (cache *client) Set(ctx context.Context, key string, value interface{}, expiredTime time.Duration) error {
+	conn := cache.conn(ctx)
 	defer func() {
 		_ = conn.Close()
 	}()
@@ -149,32 +290,101 @@ func (cache *client) Set(key string, value interface{}, expiredTime time.Duratio
 		return err
 	}
 
+	// expiry is set by same command, so key never stays without expiry
+	if milliseconds := expiredTime.Milliseconds(); milliseconds > 0 {
+		_, err = conn.Do("SET", key, b.Bytes(), "PX", milliseconds)
+		return err
+	}
+
 	_, err = conn.Do("SET", key, b.Bytes())
+	return err
+}
+
+// Delete delete key
+func (cache *client) Delete(ctx context.Context, key string) error {
+	conn := cache.conn(ctx)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_, err := conn.Do("DEL", key)
+	return err
+}
+
+// CompareAndDelete delete key only when its value is equal to value, returns false when key
+// does not exist or was changed, so only one of concurrent callers deletes key
+func (cache *client) CompareAndDelete(ctx context.Context, key string, value interface{}) (bool, error) {
+	conn := cache.conn(ctx)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	b := bytes.Buffer{}
+	err := gob.NewEncoder(&b).Encode(value)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	if expiredTime.Seconds() > 1 {
-		_, err = conn.Do("EXPIRE", key, expiredTime.Seconds())
-		if err != nil {
-			return err
-		}
+	deleted, err := redis.Int(conn.Do("EVAL", compareDeleteScript, 1, key, b.Bytes()))
+	if err != nil {
+		return false, err
 	}
 
+	return deleted == 1, nil
+}
+
+// Incr increment value of key, expired time is set atomically when key is created
+func (cache *client) Incr(ctx context.Context, key string, expiredTime time.Duration) (int, error) {
+	conn := cache.conn(ctx)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	return redis.Int(conn.Do("EVAL", incrScript, 1, key, expiredTime.Milliseconds()))
+}
+
+// AddToSet add member to set key, expired time of set is refreshed atomically
+func (cache *client) AddToSet(ctx context.Context, key string, member string, expiredTime time.Duration) error {
+	conn := cache.conn(ctx)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_, err := conn.Do("EVAL", addToSetScript, 1, key, member, expiredTime.Milliseconds())
 	return err
 }
 
-// Delete delete key
-func (cache *client) Delete(key string) error {
-	conn := cache.pool.Get()
+// GetSetMembers get members of set key
+func (cache *client) GetSetMembers(ctx context.Context, key string) ([]string, error) {
+	conn := cache.conn(ctx)
 	defer func() {
 		_ = conn.Close()
 	}()
 
-	_, err := conn.Do("DEL", key)
+	return redis.Strings(conn.Do("SMEMBERS", key))
+}
+
+// RemoveFromSet remove member from set key
+func (cache *client) RemoveFromSet(ctx context.Context, key string, member string) error {
+	conn := cache.conn(ctx)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_, err := conn.Do("SREM", key, member)
 	return err
 }
 
+// AddToStream append entry of field and value to stream, returns id of entry
+func (cache *client) AddToStream(ctx context.Context, stream string, field string, value []byte) (string, error) {
+	conn := cache.conn(ctx)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	return redis.String(conn.Do("XADD", stream, "*", field, value))
+}
+
 // Close close pool redis
 func (cache *client) Close() {
 	_ = cache.pool.Close()
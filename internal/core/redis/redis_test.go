@@ -0,0 +1,110 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/redis/redistest"
+)
+
+func newTestClient(t *testing.T) (Client, *redistest.Server) {
+	t.Helper()
+
+	server := redistest.New(t)
+	host, port := server.HostPort()
+	cache, err := Open(t.Name(), Configuration{Host: host, Port: port})
+	if err != nil {
+		t.Fatalf("open: %s", err)
+	}
+	t.Cleanup(cache.Close)
+
+	return cache, server
+}
+
+func TestExpiredTimeIsSentAsInteger(t *testing.T) {
+	ctx := context.Background()
+	expiredTime := 24*time.Hour - time.Microsecond
+
+	cache, server := newTestClient(t)
+	if err := cache.Set(ctx, "device_session:1", "value", expiredTime); err != nil {
+		t.Fatalf("set: %s", err)
+	}
+
+	if err := cache.AddToSet(ctx, "user_sessions:1", "1", expiredTime); err != nil {
+		t.Fatalf("add to set: %s", err)
+	}
+
+	for _, key := range []string{"device_session:1", "user_sessions:1"} {
+		if ttl := server.TTL(key); ttl != expiredTime.Truncate(time.Millisecond) {
+			t.Errorf("%s: expected ttl %s, got %s", key, expiredTime.Truncate(time.Millisecond), ttl)
+		}
+	}
+}
+
+func TestAddToSetRefreshesExpiredTime(t *testing.T) {
+	ctx := context.Background()
+	cache, server := newTestClient(t)
+	if err := cache.AddToSet(ctx, "user_sessions:1", "1", time.Hour); err != nil {
+		t.Fatalf("add to set: %s", err)
+	}
+
+	server.FastForward(30 * time.Minute)
+	if err := cache.AddToSet(ctx, "user_sessions:1", "2", time.Hour); err != nil {
+		t.Fatalf("add to set: %s", err)
+	}
+
+	server.FastForward(45 * time.Minute)
+	members, err := cache.GetSetMembers(ctx, "user_sessions:1")
+	if err != nil || len(members) != 2 {
+		t.Fatalf("expected members of refreshed set, got %v %v", members, err)
+	}
+
+	server.FastForward(15 * time.Minute)
+	if server.Exists("user_sessions:1") {
+		t.Fatalf("expected set to expire")
+	}
+}
+
+func TestSetLongExpiredTime(t *testing.T) {
+	ctx := context.Background()
+	cache, server := newTestClient(t)
+	if err := cache.Set(ctx, "refresh_uuid", uint(1), 24*24*time.Hour); err != nil {
+		t.Fatalf("set: %s", err)
+	}
+
+	if err := cache.Set(ctx, "no_expiry", uint(1), 0); err != nil {
+		t.Fatalf("set: %s", err)
+	}
+
+	if ttl := server.TTL("refresh_uuid"); ttl != 24*24*time.Hour {
+		t.Errorf("expected ttl of 24 days, got %s", ttl)
+	}
+
+	if ttl := server.TTL("no_expiry"); ttl != 0 || !server.Exists("no_expiry") {
+		t.Errorf("expected key without expiry, got %s", ttl)
+	}
+}
+
+func TestCompareAndDelete(t *testing.T) {
+	type entry struct {
+		Recipient string
+		Hash      string
+	}
+
+	ctx := context.Background()
+	cache, server := newTestClient(t)
+	if err := cache.Set(ctx, "otp:ABC", &entry{Recipient: "user@example.com", Hash: "hash"}, time.Minute); err != nil {
+		t.Fatalf("set: %s", err)
+	}
+
+	deleted, err := cache.CompareAndDelete(ctx, "otp:ABC", &entry{Recipient: "user@example.com", Hash: "other"})
+	if err != nil || deleted || !server.Exists("otp:ABC") {
+		t.Fatalf("expected other value not to delete key, got %v %v", deleted, err)
+	}
+
+	deleted, err = cache.CompareAndDelete(ctx, "otp:ABC", &entry{Recipient: "user@example.com", Hash: "hash"})
+	if err != nil || !deleted || server.Exists("otp:ABC") {
+		t.Fatalf("expected value of set to delete key, got %v %v", deleted, err)
+	}
+}
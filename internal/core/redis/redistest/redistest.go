@@ -0,0 +1,27 @@
+package redistest
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// Server in memory redis server for tests, lua scripts are run as by redis,
+// keys expire when time of server is fast forwarded
+type Server struct {
+	*miniredis.Miniredis
+}
+
+// New start server, server is closed when test completes
+func New(t testing.TB) *Server {
+	t.Helper()
+
+	return &Server{Miniredis: miniredis.RunT(t)}
+}
+
+// HostPort host and port of server
+func (s *Server) HostPort() (string, int) {
+	port, _ := strconv.Atoi(s.Port())
+	return s.Host(), port
+}
@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+const (
+	// Default connection name of `REDIS` config
+	Default = "redis"
+)
+
+var (
+	clients   = map[string]*client{}
+	clientsMu sync.RWMutex
+)
+
+// PoolStats stats of connection pool
+type PoolStats struct {
+	redis.PoolStats
+	Name      string
+	MaxActive int
+}
+
+// register register client, client of same name is replaced
+func register(name string, conn *client) {
+	clientsMu.Lock()
+	clients[name] = conn
+	clientsMu.Unlock()
+}
+
+// Connection client of name (redis or name of `DATABASES.REDIS` config), nil when name is not registered
+func Connection(name string) Client {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+
+	if conn, ok := clients[name]; ok {
+		return conn
+	}
+
+	return nil
+}
+
+// Stats stats of connection pools of all clients, sorted by name
+func Stats() []PoolStats {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+
+	stats := make([]PoolStats, 0, len(clients))
+	for name, conn := range clients {
+		stats = append(stats, PoolStats{
+			PoolStats: conn.pool.Stats(),
+			Name:      name,
+			MaxActive: conn.pool.MaxActive,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats
+}
@@ -1,13 +1,26 @@
 package render
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/Thospol/go-fiber/internal/core/config"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// JSON render json to client
+// versioner versioned response
+type versioner interface {
+	GetVersion() uint
+}
+
+// JSON render json to client, ETag header is set when response is versioned
 func JSON(c *fiber.Ctx, response interface{}) error {
+	if v, ok := response.(versioner); ok {
+		c.Set(fiber.HeaderETag, ETag(v.GetVersion()))
+	}
+
 	return c.
 		Status(config.RR.Internal.Success.HTTPStatusCode()).
 		JSON(response)
@@ -24,3 +37,19 @@ func Error(c *fiber.Ctx, err error) error {
 		Status(errMsg.HTTPStatusCode()).
 		JSON(errMsg.WithLocale(c))
 }
+
+// ETag entity tag of version
+func ETag(version uint) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// ParseETag parse version from entity tag of `If-Match` header
+func ParseETag(etag string) (uint, error) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	version, err := strconv.ParseUint(strings.Trim(etag, `"`), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(version), nil
+}
@@ -0,0 +1,123 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/redis"
+	"github.com/Thospol/go-fiber/internal/models"
+)
+
+const (
+	deviceSessionKey = "device_session:%s"
+	lastSeenKey      = "device_session_last_seen:%s"
+	userSessionsKey  = "user_sessions:%d"
+
+	// touchInterval minimum interval to update last seen of device session
+	touchInterval = time.Minute
+)
+
+var (
+	// ErrorNotFound error device session not found
+	ErrorNotFound = errors.New("Device session not found")
+)
+
+// Save save device session and add to index of user sessions
+func Save(ctx context.Context, ds *models.DeviceSession) error {
+	expiredTime := time.Until(ds.ExpiresAt)
+	client := redis.GetConnection()
+	if err := client.Set(ctx, fmt.Sprintf(deviceSessionKey, ds.ID), ds, expiredTime); err != nil {
+		return err
+	}
+
+	return client.AddToSet(ctx, fmt.Sprintf(userSessionsKey, ds.UserID), ds.ID, expiredTime)
+}
+
+// Get get device session by id with last seen of Touch
+func Get(ctx context.Context, id string) (*models.DeviceSession, error) {
+	client := redis.GetConnection()
+	ds := &models.DeviceSession{}
+	if err := client.Get(ctx, fmt.Sprintf(deviceSessionKey, id), ds); err != nil {
+		return nil, ErrorNotFound
+	}
+
+	var lastSeenAt time.Time
+	if err := client.Get(ctx, fmt.Sprintf(lastSeenKey, id), &lastSeenAt); err == nil && lastSeenAt.After(ds.LastSeenAt) {
+		ds.LastSeenAt = lastSeenAt
+	}
+
+	return ds, nil
+}
+
+// List list active device sessions of user, expired sessions are removed from index
+func List(ctx context.Context, userID uint) ([]*models.DeviceSession, error) {
+	client := redis.GetConnection()
+	ids, err := client.GetSetMembers(ctx, fmt.Sprintf(userSessionsKey, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []*models.DeviceSession{}
+	for _, id := range ids {
+		ds, err := Get(ctx, id)
+		if err != nil {
+			_ = client.RemoveFromSet(ctx, fmt.Sprintf(userSessionsKey, userID), id)
+			continue
+		}
+
+		sessions = append(sessions, ds)
+	}
+
+	return sessions, nil
+}
+
+// Touch update last seen of device session, last seen is stored in its own key
+// so device session written by refresh or deleted by revoke is never overwritten
+func Touch(ctx context.Context, id string) error {
+	ds, err := Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if time.Since(ds.LastSeenAt) < touchInterval {
+		return nil
+	}
+
+	return redis.GetConnection().Set(ctx, fmt.Sprintf(lastSeenKey, ds.ID), time.Now(), time.Until(ds.ExpiresAt))
+}
+
+// Revoke revoke device session of user and its token pair
+func Revoke(ctx context.Context, userID uint, id string) error {
+	client := redis.GetConnection()
+	ds, err := Get(ctx, id)
+	if err != nil || ds.UserID != userID {
+		_ = client.RemoveFromSet(ctx, fmt.Sprintf(userSessionsKey, userID), id)
+		return ErrorNotFound
+	}
+
+	for _, key := range []string{ds.AccessUUID, ds.RefreshUUID, fmt.Sprintf(deviceSessionKey, ds.ID), fmt.Sprintf(lastSeenKey, ds.ID)} {
+		if err := client.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return client.RemoveFromSet(ctx, fmt.Sprintf(userSessionsKey, userID), id)
+}
+
+// RevokeAll revoke all device sessions of user
+func RevokeAll(ctx context.Context, userID uint) error {
+	ids, err := redis.GetConnection().GetSetMembers(ctx, fmt.Sprintf(userSessionsKey, userID))
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := Revoke(ctx, userID, id); err != nil && err != ErrorNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
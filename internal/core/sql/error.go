@@ -0,0 +1,26 @@
+package sql
+
+import "strings"
+
+// uniqueViolations messages of unique violation of postgresql, mysql and sqlite
+var uniqueViolations = []string{
+	"SQLSTATE 23505",
+	"Error 1062",
+	"UNIQUE constraint failed",
+}
+
+// IsUniqueViolation check error is unique violation of database
+func IsUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := err.Error()
+	for _, violation := range uniqueViolations {
+		if strings.Contains(msg, violation) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,6 +1,7 @@
 package sql
 
 import (
+	dbsql "database/sql"
 	"fmt"
 
 	"github.com/Thospol/go-fiber/internal/core/config"
@@ -12,36 +13,83 @@ import (
 )
 
 var (
-	// Database global variable database
+	// MysqlDatabase global variable database `mysql`, same as Database(MySQL)
 	MysqlDatabase = &gorm.DB{}
 )
 
-// InitConnectionMysql open initialize a new db connection.
+// InitConnectionMysql open initialize a new db connection, sqlite is opened when `DRIVER_NAME` is sqlite.
+// connection is registered as mysql
 func InitConnectionMysql(config config.DatabaseConfig) (err error) {
-	dns := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True",
-		config.Username,
-		config.Password,
-		config.Host,
-		config.Port,
-		config.DatabaseName,
-	)
-
-	MysqlDatabase, err = gorm.Open(mysql.Open(dns), &gorm.Config{})
+	if config.DriverName == SQLite {
+		MysqlDatabase, err = openSQLite(MySQL, config)
+	} else {
+		MysqlDatabase, err = openMysql(MySQL, config)
+	}
 	if err != nil {
 		return err
 	}
 
-	sqlDB, err := MysqlDatabase.DB()
+	Register(MySQL, MysqlDatabase)
+	return nil
+}
+
+// openMysql open mysql database and its replicas, name is name of pool stats
+func openMysql(name string, config config.DatabaseConfig) (*gorm.DB, error) {
+	database, err := gorm.Open(mysql.Open(mysqlDNS(config, config.Host, config.Port)), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := database.DB()
 	if err != nil {
 		logrus.Errorf("[InitConnectionMysql] set up to connect to the database error: %s", err)
-		return err
+		return nil, err
 	}
 
 	err = sqlDB.Ping()
 	if err != nil {
 		logrus.Errorf("[InitConnectionMysql] ping database error: %s", err)
-		return err
+		return nil, err
 	}
+	applyPool(name, sqlDB, config.Pool)
 
-	return nil
+	replicas, err := openReplicas(name, config, func(host string, port int) gorm.Dialector {
+		return mysql.Open(mysqlDNS(config, host, port))
+	}, func(conn *dbsql.DB) gorm.Dialector {
+		return mysql.New(mysql.Config{Conn: conn})
+	})
+	if err != nil {
+		logrus.Errorf("[InitConnectionMysql] connect to replicas error: %s", err)
+		return nil, err
+	}
+
+	err = useReplicas(database, replicas)
+	if err != nil {
+		logrus.Errorf("[InitConnectionMysql] set up replicas error: %s", err)
+		return nil, err
+	}
+
+	err = useTimeout(database, config.QueryTimeout)
+	if err != nil {
+		logrus.Errorf("[InitConnectionMysql] set up query timeout error: %s", err)
+		return nil, err
+	}
+
+	return database, nil
+}
+
+func mysqlDNS(config config.DatabaseConfig, host string, port int) string {
+	dns := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True",
+		config.Username,
+		config.Password,
+		host,
+		port,
+		config.DatabaseName,
+	)
+
+	if config.Timeout > 0 {
+		dns += fmt.Sprintf("&timeout=%s", config.Timeout)
+	}
+
+	return dns
 }
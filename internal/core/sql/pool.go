@@ -0,0 +1,65 @@
+package sql
+
+import (
+	dbsql "database/sql"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+)
+
+var (
+	pools   = map[string]*dbsql.DB{}
+	poolsMu sync.RWMutex
+)
+
+// PoolStats stats of connection pool
+type PoolStats struct {
+	Name string
+	dbsql.DBStats
+}
+
+// Stats stats of all sql connection pools (primary and replicas), sorted by name
+func Stats() []PoolStats {
+	poolsMu.RLock()
+	defer poolsMu.RUnlock()
+
+	stats := make([]PoolStats, 0, len(pools))
+	for name, pool := range pools {
+		stats = append(stats, PoolStats{Name: name, DBStats: pool.Stats()})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats
+}
+
+// applyPool apply pool config and register pool for stats
+func applyPool(name string, sqlDB *dbsql.DB, pool config.PoolConfig) {
+	if pool.MaxOpen > 0 {
+		sqlDB.SetMaxOpenConns(pool.MaxOpen)
+	}
+
+	if pool.MaxIdle > 0 {
+		sqlDB.SetMaxIdleConns(pool.MaxIdle)
+	}
+
+	if pool.MaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(pool.MaxLifetime)
+	}
+
+	if pool.MaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(pool.MaxIdleTime)
+	}
+
+	poolsMu.Lock()
+	pools[name] = sqlDB
+	poolsMu.Unlock()
+}
+
+// replicaName pool name of replica
+func replicaName(name string, index int) string {
+	return fmt.Sprintf("%s_replica_%d", name, index)
+}
@@ -1,6 +1,7 @@
 package sql
 
 import (
+	dbsql "database/sql"
 	"fmt"
 
 	"github.com/Thospol/go-fiber/internal/core/config"
@@ -12,39 +13,86 @@ import (
 )
 
 var (
-	// PostgreDatabase global variable database `postgresql`
+	// PostgreDatabase global variable database `postgresql`, same as Database(Postgres)
 	PostgreDatabase = &gorm.DB{}
 )
 
-// InitConnectionPostgreSQL open initialize a new db connection.
+// InitConnectionPostgreSQL open initialize a new db connection, sqlite is opened when `DRIVER_NAME` is sqlite.
+// connection is registered as postgres
 func InitConnectionPostgreSQL(config config.DatabaseConfig) (err error) {
-	postgreSQLCredentials := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
-		config.Host,
-		config.Port,
-		config.Username,
-		config.Password,
-		config.DatabaseName,
-	)
+	if config.DriverName == SQLite {
+		PostgreDatabase, err = openSQLite(Postgres, config)
+	} else {
+		PostgreDatabase, err = openPostgreSQL(Postgres, config)
+	}
+	if err != nil {
+		return err
+	}
 
-	PostgreDatabase, err = gorm.Open(postgres.Open(postgreSQLCredentials), &gorm.Config{
+	Register(Postgres, PostgreDatabase)
+	return nil
+}
+
+// openPostgreSQL open postgresql database and its replicas, name is name of pool stats
+func openPostgreSQL(name string, config config.DatabaseConfig) (*gorm.DB, error) {
+	database, err := gorm.Open(postgres.Open(postgreSQLCredentials(config, config.Host, config.Port)), &gorm.Config{
 		PrepareStmt: true,
 	})
 	if err != nil {
 		logrus.Errorf("[InitConnectionPostgresqlSQL] failed to connect to the database error: %s", err)
-		return err
+		return nil, err
 	}
 
-	sqlDB, err := PostgreDatabase.DB()
+	sqlDB, err := database.DB()
 	if err != nil {
 		logrus.Errorf("[InitConnectionPostgresqlSQL] set up to connect to the database error: %s", err)
-		return err
+		return nil, err
 	}
 
 	err = sqlDB.Ping()
 	if err != nil {
 		logrus.Errorf("[InitConnectionPostgresqlSQL] ping database error: %s", err)
-		return err
+		return nil, err
 	}
+	applyPool(name, sqlDB, config.Pool)
 
-	return nil
+	replicas, err := openReplicas(name, config, func(host string, port int) gorm.Dialector {
+		return postgres.Open(postgreSQLCredentials(config, host, port))
+	}, func(conn *dbsql.DB) gorm.Dialector {
+		return postgres.New(postgres.Config{Conn: conn})
+	})
+	if err != nil {
+		logrus.Errorf("[InitConnectionPostgresqlSQL] connect to replicas error: %s", err)
+		return nil, err
+	}
+
+	err = useReplicas(database, replicas)
+	if err != nil {
+		logrus.Errorf("[InitConnectionPostgresqlSQL] set up replicas error: %s", err)
+		return nil, err
+	}
+
+	err = useTimeout(database, config.QueryTimeout)
+	if err != nil {
+		logrus.Errorf("[InitConnectionPostgresqlSQL] set up query timeout error: %s", err)
+		return nil, err
+	}
+
+	return database, nil
+}
+
+func postgreSQLCredentials(config config.DatabaseConfig, host string, port int) string {
+	credentials := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
+		host,
+		port,
+		config.Username,
+		config.Password,
+		config.DatabaseName,
+	)
+
+	if seconds := int(config.Timeout.Seconds()); seconds > 0 {
+		credentials += fmt.Sprintf(" connect_timeout=%d", seconds)
+	}
+
+	return credentials
 }
@@ -0,0 +1,67 @@
+package sql
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+
+	"gorm.io/gorm"
+)
+
+var (
+	databases   = map[string]*gorm.DB{}
+	databasesMu sync.RWMutex
+)
+
+// Register register database of name, database of same name is replaced
+func Register(name string, database *gorm.DB) {
+	databasesMu.Lock()
+	databases[name] = database
+	databasesMu.Unlock()
+}
+
+// Database database of name (postgres, mysql or name of `DATABASES.SQL` config), nil when name is not registered
+func Database(name string) *gorm.DB {
+	databasesMu.RLock()
+	defer databasesMu.RUnlock()
+
+	return databases[name]
+}
+
+// Names names of registered databases, sorted
+func Names() []string {
+	databasesMu.RLock()
+	defer databasesMu.RUnlock()
+
+	names := make([]string, 0, len(databases))
+	for name := range databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Open open database of name by `DRIVER_NAME` (postgres, mysql or sqlite) and register it
+func Open(name string, config config.DatabaseConfig) (*gorm.DB, error) {
+	var database *gorm.DB
+	var err error
+	switch config.DriverName {
+	case Postgres, "":
+		database, err = openPostgreSQL(name, config)
+	case MySQL:
+		database, err = openMysql(name, config)
+	case SQLite:
+		database, err = openSQLite(name, config)
+	default:
+		err = fmt.Errorf("unsupported driver: %s", config.DriverName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	Register(name, database)
+	return database, nil
+}
@@ -0,0 +1,111 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"sync/atomic"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+const stickyCallback = "sql:sticky_primary"
+
+type stickyKey struct{}
+
+// Sticky primary state of request, reads are routed to primary after it is marked
+type Sticky struct {
+	primary int32
+}
+
+// Mark route following reads to primary
+func (s *Sticky) Mark() {
+	atomic.StoreInt32(&s.primary, 1)
+}
+
+// Primary reads are routed to primary
+func (s *Sticky) Primary() bool {
+	return atomic.LoadInt32(&s.primary) == 1
+}
+
+// WithSticky context with sticky primary state, writes through database of context mark the state
+func WithSticky(ctx context.Context, s *Sticky) context.Context {
+	return context.WithValue(ctx, stickyKey{}, s)
+}
+
+// Primary database of which reads are routed to primary
+func Primary(database *gorm.DB) *gorm.DB {
+	return database.Clauses(dbresolver.Write)
+}
+
+// openReplicas open connections of replicas with pool config of primary,
+// opened connections are wrapped into dialectors of resolver
+func openReplicas(name string, conf config.DatabaseConfig, open func(host string, port int) gorm.Dialector, wrap func(conn *dbsql.DB) gorm.Dialector) ([]gorm.Dialector, error) {
+	replicas := []gorm.Dialector{}
+	for i, replica := range conf.Replicas {
+		database, err := gorm.Open(open(replica.Host, replica.Port), &gorm.Config{})
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := database.DB()
+		if err != nil {
+			return nil, err
+		}
+
+		applyPool(replicaName(name, i), conn, conf.Pool)
+		replicas = append(replicas, wrap(conn))
+	}
+
+	return replicas, nil
+}
+
+// useReplicas route reads to replicas, writes and transactions stay on primary
+func useReplicas(database *gorm.DB, replicas []gorm.Dialector) error {
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	err := database.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}))
+	if err != nil {
+		return err
+	}
+
+	callback := database.Callback()
+	for _, err := range []error{
+		callback.Create().After("gorm:create").Register(stickyCallback, markSticky),
+		callback.Update().After("gorm:update").Register(stickyCallback, markSticky),
+		callback.Delete().After("gorm:delete").Register(stickyCallback, markSticky),
+		callback.Query().After("gorm:db_resolver").Before("gorm:query").Register(stickyCallback, routeSticky),
+		callback.Row().After("gorm:db_resolver").Before("gorm:row").Register(stickyCallback, routeSticky),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// markSticky mark sticky state of context after write
+func markSticky(db *gorm.DB) {
+	if s, ok := db.Statement.Context.Value(stickyKey{}).(*Sticky); ok && db.Error == nil {
+		s.Mark()
+	}
+}
+
+// routeSticky route read back to primary when sticky state of context is marked, transaction is always on primary
+func routeSticky(db *gorm.DB) {
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok {
+		return
+	}
+
+	if s, ok := db.Statement.Context.Value(stickyKey{}).(*Sticky); ok && s.Primary() {
+		db.Statement.ConnPool = db.Config.ConnPool
+	}
+}
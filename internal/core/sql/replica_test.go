@@ -0,0 +1,104 @@
+package sql
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+type replicaItem struct {
+	ID   uint
+	Name string
+}
+
+func openReplicaTestDatabase(t *testing.T, path string) *gorm.DB {
+	t.Helper()
+
+	database, err := gorm.Open(sqlite.Dialector{DriverName: SQLite, DSN: path}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open %s: %s", path, err)
+	}
+
+	if err := database.AutoMigrate(&replicaItem{}); err != nil {
+		t.Fatalf("migrate %s: %s", path, err)
+	}
+
+	return database
+}
+
+func TestStickyPrimaryAfterWrite(t *testing.T) {
+	dir := t.TempDir()
+	primary := openReplicaTestDatabase(t, filepath.Join(dir, "primary.db"))
+	replica := openReplicaTestDatabase(t, filepath.Join(dir, "replica.db"))
+	if err := replica.Create(&replicaItem{ID: 1, Name: "replica"}).Error; err != nil {
+		t.Fatalf("seed replica: %s", err)
+	}
+
+	conn, err := replica.DB()
+	if err != nil {
+		t.Fatalf("replica conn: %s", err)
+	}
+
+	err = useReplicas(primary, []gorm.Dialector{sqlite.Dialector{DriverName: SQLite, Conn: conn}})
+	if err != nil {
+		t.Fatalf("use replicas: %s", err)
+	}
+
+	sticky := &Sticky{}
+	database := primary.WithContext(WithSticky(context.Background(), sticky))
+
+	item := replicaItem{}
+	if err := database.First(&item, 1).Error; err != nil || item.Name != "replica" {
+		t.Fatalf("read before write should go to replica, got %q error: %v", item.Name, err)
+	}
+
+	if err := database.Save(&replicaItem{ID: 1, Name: "primary"}).Error; err != nil {
+		t.Fatalf("write: %s", err)
+	}
+
+	if !sticky.Primary() {
+		t.Fatal("write should mark sticky state")
+	}
+
+	item = replicaItem{}
+	if err := database.First(&item, 1).Error; err != nil || item.Name != "primary" {
+		t.Fatalf("read after write should go to primary, got %q error: %v", item.Name, err)
+	}
+
+	items := []replicaItem{}
+	if err := database.Find(&items).Error; err != nil || len(items) != 1 || items[0].Name != "primary" {
+		t.Fatalf("find after write should go to primary, got %+v error: %v", items, err)
+	}
+
+	var name string
+	if err := database.Model(&replicaItem{}).Where("id = ?", 1).Select("name").Row().Scan(&name); err != nil || name != "primary" {
+		t.Fatalf("row after write should go to primary, got %q error: %v", name, err)
+	}
+}
+
+func TestPrimaryReadsFromPrimary(t *testing.T) {
+	dir := t.TempDir()
+	primary := openReplicaTestDatabase(t, filepath.Join(dir, "primary.db"))
+	replica := openReplicaTestDatabase(t, filepath.Join(dir, "replica.db"))
+	if err := primary.Create(&replicaItem{ID: 1, Name: "primary"}).Error; err != nil {
+		t.Fatalf("seed primary: %s", err)
+	}
+
+	conn, err := replica.DB()
+	if err != nil {
+		t.Fatalf("replica conn: %s", err)
+	}
+
+	err = useReplicas(primary, []gorm.Dialector{sqlite.Dialector{DriverName: SQLite, Conn: conn}})
+	if err != nil {
+		t.Fatalf("use replicas: %s", err)
+	}
+
+	item := replicaItem{}
+	if err := Primary(primary).First(&item, 1).Error; err != nil || item.Name != "primary" {
+		t.Fatalf("primary read should go to primary, got %q error: %v", item.Name, err)
+	}
+}
@@ -0,0 +1,60 @@
+package sql
+
+import (
+	"strings"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+
+	"github.com/glebarez/sqlite"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// SQLite driver name of sqlite, set as `DRIVER_NAME` of postgresql or mysql config
+	// for local development and tests without database server
+	SQLite = "sqlite"
+
+	sqliteMemory = "file::memory:?cache=shared"
+)
+
+// openSQLite open sqlite database, `DATABASE_NAME` is file path, in memory database when empty
+func openSQLite(name string, config config.DatabaseConfig) (*gorm.DB, error) {
+	dsn := config.DatabaseName
+	if dsn == "" {
+		dsn = sqliteMemory
+	}
+
+	database, err := gorm.Open(sqlite.Dialector{DriverName: SQLite, DSN: dsn}, &gorm.Config{})
+	if err != nil {
+		logrus.Errorf("[openSQLite] failed to open the database error: %s", err)
+		return nil, err
+	}
+
+	sqlDB, err := database.DB()
+	if err != nil {
+		logrus.Errorf("[openSQLite] set up to connect to the database error: %s", err)
+		return nil, err
+	}
+	applyPool(name, sqlDB, config.Pool)
+
+	// in memory database is destroyed when its last connection is closed,
+	// idle connections (at least 1 by default) are kept open
+	if isMemory(dsn) {
+		sqlDB.SetConnMaxIdleTime(0)
+		sqlDB.SetConnMaxLifetime(0)
+	}
+
+	err = useTimeout(database, config.QueryTimeout)
+	if err != nil {
+		logrus.Errorf("[openSQLite] set up query timeout error: %s", err)
+		return nil, err
+	}
+
+	return database, nil
+}
+
+// isMemory dsn is in memory database
+func isMemory(dsn string) bool {
+	return strings.Contains(dsn, ":memory:") || strings.Contains(dsn, "mode=memory")
+}
@@ -0,0 +1,66 @@
+package sql
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	timeoutStartCallback = "sql:timeout_start"
+	timeoutStopCallback  = "sql:timeout_stop"
+	timeoutContextKey    = "sql:timeout_context"
+	timeoutCancelKey     = "sql:timeout_cancel"
+)
+
+// useTimeout bound each create, query, update, delete and raw exec by timeout,
+// earlier deadline of statement context (request deadline) is kept. rows of Row/Rows are read
+// after callbacks, so they are bounded by statement context only
+func useTimeout(database *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	start := startTimeout(timeout)
+	callback := database.Callback()
+	for _, err := range []error{
+		callback.Create().Before("gorm:before_create").Register(timeoutStartCallback, start),
+		callback.Create().After("gorm:after_create").Register(timeoutStopCallback, stopTimeout),
+		callback.Query().Before("gorm:query").Register(timeoutStartCallback, start),
+		callback.Query().After("gorm:after_query").Register(timeoutStopCallback, stopTimeout),
+		callback.Update().Before("gorm:setup_reflect_value").Register(timeoutStartCallback, start),
+		callback.Update().After("gorm:after_update").Register(timeoutStopCallback, stopTimeout),
+		callback.Delete().Before("gorm:before_delete").Register(timeoutStartCallback, start),
+		callback.Delete().After("gorm:after_delete").Register(timeoutStopCallback, stopTimeout),
+		callback.Raw().Before("gorm:raw").Register(timeoutStartCallback, start),
+		callback.Raw().After("gorm:raw").Register(timeoutStopCallback, stopTimeout),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startTimeout replace statement context with context of timeout
+func startTimeout(timeout time.Duration) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, cancel := context.WithTimeout(db.Statement.Context, timeout)
+		db.InstanceSet(timeoutContextKey, db.Statement.Context)
+		db.InstanceSet(timeoutCancelKey, cancel)
+		db.Statement.Context = ctx
+	}
+}
+
+// stopTimeout cancel context of timeout and restore statement context, statement may be reused by chained calls
+func stopTimeout(db *gorm.DB) {
+	if cancel, ok := db.InstanceGet(timeoutCancelKey); ok {
+		cancel.(context.CancelFunc)()
+	}
+
+	if ctx, ok := db.InstanceGet(timeoutContextKey); ok {
+		db.Statement.Context = ctx.(context.Context)
+	}
+}
@@ -0,0 +1,123 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// Postgres database name of postgresql connection and driver name of postgresql
+	Postgres = "postgres"
+	// MySQL database name of mysql connection and driver name of mysql
+	MySQL = "mysql"
+)
+
+var savepointSeq uint64
+
+type transactionKey struct {
+	name string
+}
+
+// WithTransaction context with transaction of database name
+func WithTransaction(ctx context.Context, name string, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, transactionKey{name: name}, tx)
+}
+
+// TransactionFromContext transaction of database name in context, nil when there is no transaction
+func TransactionFromContext(ctx context.Context, name string) *gorm.DB {
+	tx, _ := ctx.Value(transactionKey{name: name}).(*gorm.DB)
+	return tx
+}
+
+// FromContext transaction of database name in context or registered database when there is no transaction,
+// database is bound to ctx
+func FromContext(ctx context.Context, name string) *gorm.DB {
+	database := TransactionFromContext(ctx, name)
+	if database == nil {
+		database = Database(name)
+	}
+
+	if database == nil || database.Statement == nil {
+		return database
+	}
+
+	return database.WithContext(ctx)
+}
+
+// WithinTransaction run fn in transaction of postgres database, see WithinTransactionOf
+func WithinTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return WithinTransactionOf(ctx, Postgres, fn)
+}
+
+// WithinTransactionOf run fn in transaction of database name, fn is run in a savepoint
+// when transaction of ctx is active (request transaction or outer WithinTransaction),
+// otherwise in a new transaction. changes of fn are rolled back when fn returns error or panics,
+// context of tx carries the transaction, so nested calls with tx.Statement.Context use savepoints
+func WithinTransactionOf(ctx context.Context, name string, fn func(tx *gorm.DB) error) error {
+	database := FromContext(ctx, name)
+	if database == nil || database.Statement == nil {
+		return fmt.Errorf("database %s is not connected", name)
+	}
+
+	if isTransaction(database) {
+		return withinSavepoint(database, fn)
+	}
+
+	return database.Transaction(func(tx *gorm.DB) error {
+		return fn(tx.WithContext(WithTransaction(ctx, name, tx)))
+	})
+}
+
+// WithinTransactionOn run fn in transaction of database, fn is run in a savepoint
+// when database is a transaction (e.g. tx of database.Transaction), otherwise in a new transaction
+func WithinTransactionOn(database *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if database == nil || database.Statement == nil {
+		return fmt.Errorf("database is not connected")
+	}
+
+	if isTransaction(database) {
+		return withinSavepoint(database, fn)
+	}
+
+	return database.Transaction(fn)
+}
+
+// isTransaction database is a transaction
+func isTransaction(database *gorm.DB) bool {
+	committer, ok := database.Statement.ConnPool.(gorm.TxCommitter)
+	return ok && committer != nil
+}
+
+// withinSavepoint run fn in savepoint of transaction, savepoint is released on success
+// and rolled back when fn returns error or panics
+func withinSavepoint(tx *gorm.DB, fn func(tx *gorm.DB) error) (err error) {
+	// savepoint names are unique, mysql replaces savepoint of same name
+	savepoint := fmt.Sprintf("sp_%d", atomic.AddUint64(&savepointSeq, 1))
+	if err := tx.SavePoint(savepoint).Error; err != nil {
+		return err
+	}
+
+	panicked := true
+	defer func() {
+		if panicked || err != nil {
+			if rollbackErr := tx.RollbackTo(savepoint).Error; rollbackErr != nil {
+				logrus.Errorf("[withinSavepoint] rollback to savepoint %s error: %s", savepoint, rollbackErr)
+				if err != nil {
+					err = fmt.Errorf("%w (rollback to savepoint: %s)", err, rollbackErr)
+				}
+			}
+		}
+	}()
+
+	err = fn(tx)
+	panicked = false
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(fmt.Sprintf("RELEASE SAVEPOINT %s", savepoint)).Error
+}
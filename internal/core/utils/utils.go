@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
@@ -275,6 +276,12 @@ func MD5HashEncode(text string) string {
 	return base64.StdEncoding.EncodeToString(hash)
 }
 
+// SHA256HashHex sha256 hash byte to string hex
+func SHA256HashHex(text string) string {
+	hashByte := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(hashByte[:])
+}
+
 // FindNumberFromText find number from string
 func FindNumberFromText(text string) int {
 	slice := regexp.MustCompile("[0-9]+").FindAllString(text, -1)
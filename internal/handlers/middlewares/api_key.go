@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/context"
+	"github.com/Thospol/go-fiber/internal/core/utils"
+	"github.com/Thospol/go-fiber/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+
+	// lastUsedInterval minimum interval to update last used of api key
+	lastUsedInterval = time.Minute
+)
+
+// RequireAPIKey require api key in header `X-API-Key` with all scopes
+func RequireAPIKey(scopes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(apiKeyHeader)
+		if key == "" {
+			logrus.Error("[RequireAPIKey] api key is empty")
+			return c.
+				Status(config.RR.Internal.Unauthorized.HTTPStatusCode()).
+				JSON(config.RR.Internal.Unauthorized.WithLocale(c))
+		}
+
+		database := context.New(c).GetPostgreDatabase()
+		apiKey := &models.APIKey{}
+		err := database.Where("key_hash = ?", utils.SHA256HashHex(key)).First(apiKey).Error
+		if err != nil || apiKey.IsExpired() {
+			logrus.Error("[RequireAPIKey] find api key error: ", config.RR.Internal.Unauthorized.Error())
+			return c.
+				Status(config.RR.Internal.Unauthorized.HTTPStatusCode()).
+				JSON(config.RR.Internal.Unauthorized.WithLocale(c))
+		}
+
+		service := &models.ServiceSession{
+			APIKeyID: apiKey.ID,
+			Name:     apiKey.Name,
+			Scopes:   apiKey.GetScopes(),
+		}
+
+		for _, scope := range scopes {
+			if !service.HasScope(scope) {
+				logrus.Errorf("[RequireAPIKey] scope '%s' denied", scope)
+				return c.
+					Status(config.RR.Internal.Forbidden.HTTPStatusCode()).
+					JSON(config.RR.Internal.Forbidden.WithLocale(c))
+			}
+		}
+
+		now := time.Now()
+		if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastUsedInterval {
+			err = database.Model(apiKey).UpdateColumn("last_used_at", now).Error
+			if err != nil {
+				logrus.Errorf("[RequireAPIKey] update last used error: %s", err)
+			}
+		}
+
+		// Add the service session to locals
+		c.Locals(context.ServiceKey, service)
+		return c.Next()
+	}
+}
+
+// RequireAuthenticationOrAPIKey require api key when header `X-API-Key` is sent, otherwise require authentication,
+// use before RequirePermission so users are checked by permissions and services by scopes
+func RequireAuthenticationOrAPIKey() fiber.Handler {
+	apiKey := RequireAPIKey()
+	authentication := RequireAuthentication()
+	return func(c *fiber.Ctx) error {
+		if c.Get(apiKeyHeader) != "" {
+			return apiKey(c)
+		}
+
+		return authentication(c)
+	}
+}
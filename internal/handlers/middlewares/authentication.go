@@ -7,10 +7,11 @@ import (
 	"github.com/Thospol/go-fiber/internal/core/context"
 	"github.com/Thospol/go-fiber/internal/core/jwt"
 	"github.com/Thospol/go-fiber/internal/core/redis"
+	"github.com/Thospol/go-fiber/internal/core/session"
 	"github.com/Thospol/go-fiber/internal/models"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/session"
+	fibersession "github.com/gofiber/fiber/v2/middleware/session"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,7 +23,7 @@ const (
 // RequireAuthentication require authentication
 func RequireAuthentication() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		store := session.New()
+		store := fibersession.New()
 		sess, err := store.Get(c)
 		if err != nil {
 			panic(err)
@@ -40,14 +41,19 @@ func RequireAuthentication() fiber.Handler {
 				JSON(config.RR.Internal.Unauthorized.WithLocale(c))
 		}
 
+		ctx := context.New(c).RequestContext()
 		user, err := extractTokenMetadata(claims)
-		if err != nil || redis.GetConnection().Get(user.AccessUUID, &user.Id) != nil {
+		if err != nil || redis.GetConnection().Get(ctx, user.AccessUUID, &user.Id) != nil {
 			logrus.Error("[RequireAuthentication] extract token metadata error: ", config.RR.Internal.Unauthorized.Error())
 			return c.
 				Status(config.RR.Internal.Unauthorized.HTTPStatusCode()).
 				JSON(config.RR.Internal.Unauthorized.WithLocale(c))
 		}
 
+		if err := session.Touch(ctx, user.SessionID); err != nil {
+			logrus.Errorf("[RequireAuthentication] touch device session error: %s", err)
+		}
+
 		// Add the user session to locals
 		c.Locals(context.UserKey, user)
 		return c.Next()
@@ -56,7 +62,7 @@ func RequireAuthentication() fiber.Handler {
 
 func extractToken(c *fiber.Ctx) string {
 	token := strings.Replace(c.Get(authHeader), prefixHeaderValue, "", 1)
-	return token
+	return strings.TrimSpace(token)
 }
 
 func verifyToken(c *fiber.Ctx) (map[string]interface{}, error) {
@@ -69,13 +75,33 @@ func verifyToken(c *fiber.Ctx) (map[string]interface{}, error) {
 }
 
 func extractTokenMetadata(claims map[string]interface{}) (*models.UserSession, error) {
-	userId, _ := claims["sub"].(uint)
+	if tokenType, _ := claims["type"].(string); tokenType != jwt.AccessToken {
+		return nil, config.RR.InvalidToken
+	}
+
+	userId, _ := claims["sub"].(float64)
+	sessionID, _ := claims["session_id"].(string)
 	accessUUID, _ := claims["access_uuid"].(string)
 	refreshUUID, _ := claims["refresh_uuid"].(string)
+	role, _ := claims["role"].(string)
+	twoFactor, _ := claims["two_factor"].(bool)
+	permissions := []string{}
+	if values, ok := claims["permissions"].([]interface{}); ok {
+		for _, value := range values {
+			if permission, ok := value.(string); ok {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+
 	userSession := &models.UserSession{
-		Id:          userId,
+		Id:          uint(userId),
+		SessionID:   sessionID,
 		AccessUUID:  accessUUID,
 		RefreshUUID: refreshUUID,
+		Role:        role,
+		Permissions: permissions,
+		TwoFactor:   twoFactor,
 	}
 
 	return userSession, nil
@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// RequirePermission require all permissions, use after RequireAuthentication or RequireAPIKey
+func RequirePermission(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := context.New(c)
+		user, userErr := ctx.GetUser()
+		_, serviceErr := ctx.GetService()
+		if userErr != nil && serviceErr != nil {
+			logrus.Error("[RequirePermission] get user error: ", config.RR.Internal.Unauthorized.Error())
+			return c.
+				Status(config.RR.Internal.Unauthorized.HTTPStatusCode()).
+				JSON(config.RR.Internal.Unauthorized.WithLocale(c))
+		}
+
+		if userErr == nil && !user.TwoFactor && config.CF.TwoFactorRequired(user.Role) {
+			logrus.Errorf("[RequirePermission] role '%s' requires two factor authentication", user.Role)
+			return c.
+				Status(config.RR.Internal.Forbidden.HTTPStatusCode()).
+				JSON(config.RR.Internal.Forbidden.WithLocale(c))
+		}
+
+		for _, permission := range permissions {
+			if !ctx.HasPermission(permission) {
+				logrus.Errorf("[RequirePermission] permission '%s' denied", permission)
+				return c.
+					Status(config.RR.Internal.Forbidden.HTTPStatusCode()).
+					JSON(config.RR.Internal.Forbidden.WithLocale(c))
+			}
+		}
+
+		return c.Next()
+	}
+}
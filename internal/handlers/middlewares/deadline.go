@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	gocontext "context"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/context"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Deadline set deadline of request context, database calls through request context are cancelled after timeout.
+// fasthttp does not report client disconnect, so disconnected client does not cancel database calls before deadline
+func Deadline(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if timeout <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := gocontext.WithTimeout(c.Context(), timeout)
+		defer cancel()
+
+		c.Locals(context.DeadlineKey, ctx)
+		return c.Next()
+	}
+}
@@ -45,7 +45,19 @@ func Logger() fiber.Handler {
 			logs["user_id"] = user.Id
 		}
 
-		if parameters := c.Locals(context.ParametersKey); parameters != nil {
+		if service, ok := c.Locals(context.ServiceKey).(*models.ServiceSession); ok {
+			logs["service"] = service.Name
+			logs["api_key_id"] = service.APIKeyID
+		}
+
+		// bodies of routes with tokens, otp codes, totp secrets or api keys are never logged
+		response := string(c.Response().Body())
+		hideBody := hasSecretBody(strings.ToLower(c.Path()))
+		if hideBody {
+			response = "[FILTERED]"
+		}
+
+		if parameters := c.Locals(context.ParametersKey); parameters != nil && !hideBody {
 			formValue := reflect.ValueOf(parameters)
 			if formValue.Kind() == reflect.Ptr {
 				formValue = formValue.Elem()
@@ -66,16 +78,35 @@ func Logger() fiber.Handler {
 		}
 
 		if !strings.HasPrefix(c.OriginalURL(), fmt.Sprintf("%s/swagger", config.CF.Swagger.BaseURL)) {
-			logrus.WithFields(logs).Infof("[%s][%s] response: %v", c.Method(), c.OriginalURL(), string(c.Response().Body()))
+			logrus.WithFields(logs).Infof("[%s][%s] response: %v", c.Method(), c.OriginalURL(), response)
 		}
 
 		return nil
 	}
 }
 
+// secretBodyPaths prefixes of routes which request or response body contains secret
+var secretBodyPaths = []string{
+	"/api/v1/auth",
+	"/api/v1/account",
+	"/api/v1/otp",
+	"/api/v1/api-keys",
+}
+
+func hasSecretBody(path string) bool {
+	for _, prefix := range secretBodyPaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func isAboutPassword(fieldName string) bool {
 	return fieldName == "Password" ||
 		fieldName == "CurrentPassword" ||
 		fieldName == "NewPassword" ||
+		fieldName == "ConfirmPassword" ||
 		fieldName == "Pin"
 }
@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Thospol/go-fiber/internal/core/context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type loggerTestRequest struct {
+	Email        string
+	Password     string
+	RefreshToken string
+}
+
+func TestLoggerFiltersSecretBody(t *testing.T) {
+	out := &bytes.Buffer{}
+	logrus.SetOutput(out)
+	defer logrus.SetOutput(logrus.StandardLogger().Out)
+
+	app := fiber.New()
+	app.Use(Logger())
+	handler := func(c *fiber.Ctx) error {
+		c.Locals(context.ParametersKey, &loggerTestRequest{
+			Email:        "user@example.com",
+			Password:     "request-password",
+			RefreshToken: "request-refresh-token",
+		})
+		return c.SendString("response-access-token")
+	}
+	app.Post("/api/v1/auth/refresh", handler)
+	app.Post("/api/v1/users", handler)
+
+	for path, leaked := range map[string][]string{
+		"/api/v1/auth/refresh": {"user@example.com", "request-password", "request-refresh-token", "response-access-token"},
+		"/API/V1/AUTH/refresh": {"user@example.com", "request-password", "request-refresh-token", "response-access-token"},
+		"/api/v1/users":        {"request-password"},
+	} {
+		out.Reset()
+		if _, err := app.Test(httptest.NewRequest(fiber.MethodPost, path, nil)); err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+
+		if out.Len() == 0 {
+			t.Fatalf("%s: expected request log", path)
+		}
+
+		for _, secret := range leaked {
+			if strings.Contains(out.String(), secret) {
+				t.Errorf("%s: log contains %q: %s", path, secret, out.String())
+			}
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package middlewares
 
 import (
+	dbsql "database/sql"
+	"errors"
 	"fmt"
-	"net/http"
 
 	"github.com/Thospol/go-fiber/internal/core/context"
 	"github.com/Thospol/go-fiber/internal/core/sql"
@@ -11,62 +12,46 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// TransactionPostgresql to do transaction postgresql
-func TransactionPostgresql(next http.Handler) fiber.Handler {
+// Transaction run next handlers in transaction of database name (postgres, mysql),
+// opts set isolation level and read only (nil is driver default).
+// transaction is committed when response status is 2xx, rolled back on error, panic, other status
+// or deadline of request
+func Transaction(dbName string, opts *dbsql.TxOptions) fiber.Handler {
 	return func(c *fiber.Ctx) (err error) {
-		database := sql.PostgreDatabase.Begin()
-		c.Locals(context.PostgreDatabaseKey, database)
-		err = c.Next()
-		if err != nil {
-			_ = database.Rollback()
-			return
-		}
-
-		if r := recover(); r != nil && r != http.ErrAbortHandler {
-			_ = database.Rollback()
-
-			var ok bool
-			if err, ok = r.(error); !ok {
-				// Set error that will call the global error handler
-				err = fmt.Errorf("%v", r)
-				logrus.Panic(err)
-			}
+		database := sql.Database(dbName)
+		if database == nil || database.Statement == nil {
+			return fmt.Errorf("database %s is not connected", dbName)
 		}
 
-		if database.Commit().Error != nil {
-			_ = database.Rollback()
+		tx := database.WithContext(context.New(c).RequestContext()).Begin(opts)
+		if tx.Error != nil {
+			logrus.Errorf("[Transaction] begin transaction error: %s", tx.Error)
+			return tx.Error
 		}
 
-		return
-	}
-}
+		defer func() {
+			if r := recover(); r != nil {
+				_ = tx.Rollback()
+				panic(r)
+			}
+		}()
 
-// TransactionMysql to do transaction mysql
-func TransactionMysql(next http.Handler) fiber.Handler {
-	return func(c *fiber.Ctx) (err error) {
-		database := sql.MysqlDatabase.Begin()
-		c.Locals(context.MysqlDatabaseKey, database)
+		c.Locals(context.DatabaseKey(dbName), tx)
 		err = c.Next()
-		if err != nil {
-			_ = database.Rollback()
-			return
-		}
-
-		if r := recover(); r != nil && r != http.ErrAbortHandler {
-			_ = database.Rollback()
-
-			var ok bool
-			if err, ok = r.(error); !ok {
-				// Set error that will call the global error handler
-				err = fmt.Errorf("%v", r)
-				logrus.Panic(err)
+		status := c.Response().StatusCode()
+		if err != nil || status < fiber.StatusOK || status >= fiber.StatusMultipleChoices {
+			// transaction of request context is already rolled back at deadline of request
+			if rollbackErr := tx.Rollback().Error; rollbackErr != nil && !errors.Is(rollbackErr, dbsql.ErrTxDone) {
+				logrus.Errorf("[Transaction] rollback error: %s", rollbackErr)
 			}
+			return err
 		}
 
-		if database.Commit().Error != nil {
-			_ = database.Rollback()
+		if err := tx.Commit().Error; err != nil {
+			logrus.Errorf("[Transaction] commit error: %s", err)
+			return err
 		}
 
-		return
+		return nil
 	}
 }
@@ -0,0 +1,107 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/context"
+	"github.com/Thospol/go-fiber/internal/core/sql"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const transactionTestDatabase = "transaction_test"
+
+type transactionItem struct {
+	ID   uint
+	Name string
+}
+
+func newTransactionTestApp(t *testing.T) (*fiber.App, *gorm.DB) {
+	t.Helper()
+
+	database, err := sql.Open(transactionTestDatabase, config.DatabaseConfig{
+		DriverName:   sql.SQLite,
+		DatabaseName: "file:transaction_test?mode=memory&cache=shared",
+		Pool: config.PoolConfig{
+			MaxIdleTime: time.Millisecond,
+			MaxLifetime: time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %s", err)
+	}
+
+	if err := database.Migrator().DropTable(&transactionItem{}); err != nil {
+		t.Fatalf("drop table: %s", err)
+	}
+
+	if err := database.AutoMigrate(&transactionItem{}); err != nil {
+		t.Fatalf("migrate: %s", err)
+	}
+
+	app := fiber.New()
+	app.Post("/items/:status", Transaction(transactionTestDatabase, nil), func(c *fiber.Ctx) error {
+		tx := context.New(c).GetDatabase(transactionTestDatabase)
+		if err := tx.Create(&transactionItem{Name: c.Params("status")}).Error; err != nil {
+			return err
+		}
+
+		// nested transaction of request is a savepoint, its changes are rolled back alone
+		_ = sql.WithinTransactionOf(tx.Statement.Context, transactionTestDatabase, func(tx *gorm.DB) error {
+			if err := tx.Create(&transactionItem{Name: "savepoint"}).Error; err != nil {
+				return err
+			}
+			return errors.New("rollback savepoint")
+		})
+
+		switch c.Params("status") {
+		case "error":
+			return errors.New("handler error")
+		case "bad":
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	return app, database
+}
+
+func TestTransaction(t *testing.T) {
+	app, database := newTransactionTestApp(t)
+
+	for _, tc := range []struct {
+		status string
+		code   int
+	}{
+		{status: "ok", code: fiber.StatusOK},
+		{status: "bad", code: fiber.StatusBadRequest},
+		{status: "error", code: fiber.StatusInternalServerError},
+	} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/items/"+tc.status, nil))
+		if err != nil {
+			t.Fatalf("request %s: %s", tc.status, err)
+		}
+
+		if resp.StatusCode != tc.code {
+			t.Fatalf("request %s: status %d, want %d", tc.status, resp.StatusCode, tc.code)
+		}
+	}
+
+	// idle connections are closed after MaxIdleTime on file database, in memory database must survive it
+	time.Sleep(20 * time.Millisecond)
+
+	items := []transactionItem{}
+	if err := database.Find(&items).Error; err != nil {
+		t.Fatalf("find items: %s", err)
+	}
+
+	if len(items) != 1 || items[0].Name != "ok" {
+		t.Fatalf("only item of 2xx response should be committed, got %+v", items)
+	}
+}
@@ -8,8 +8,15 @@ import (
 	"time"
 
 	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/sql"
 	"github.com/Thospol/go-fiber/internal/handlers"
 	"github.com/Thospol/go-fiber/internal/handlers/middlewares"
+	"github.com/Thospol/go-fiber/internal/pkg/account"
+	"github.com/Thospol/go-fiber/internal/pkg/apikey"
+	"github.com/Thospol/go-fiber/internal/pkg/auditlog"
+	"github.com/Thospol/go-fiber/internal/pkg/auth"
+	"github.com/Thospol/go-fiber/internal/pkg/otp"
+	"github.com/Thospol/go-fiber/internal/pkg/system"
 	"github.com/Thospol/go-fiber/internal/pkg/user"
 
 	swagger "github.com/arsmn/fiber-swagger/v2"
@@ -26,7 +33,30 @@ const (
 	MaximumSize100MB = 1024 * 1024 * 100
 )
 
+// NewRouter new router and start server, server is shut down on interrupt
 func NewRouter() {
+	app := New()
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	go func() {
+		<-c
+		_, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		logrus.Info("Gracefully shutting down...")
+		_ = app.Shutdown()
+	}()
+
+	logrus.Infof("Start server on port: %d ...", config.CF.App.Port)
+	err := app.Listen(fmt.Sprintf(":%d", config.CF.App.Port))
+	if err != nil {
+		logrus.Panic(err)
+	}
+}
+
+// New new fiber app with middlewares and routes of api
+func New() *fiber.App {
 	app := fiber.New(
 		fiber.Config{
 			IdleTimeout:  5 * time.Second,
@@ -46,34 +76,67 @@ func NewRouter() {
 		Compress: true,
 	})
 
+	authEndpoint := auth.NewEndpoint()
+	app.Get("/.well-known/jwks.json", handlers.Cache(5*time.Minute), authEndpoint.JWKS)
+
 	api := app.Group("/api")
 	v1 := api.Group("/v1")
 	v1.Use(middlewares.AcceptLanguage())
+	v1.Use(middlewares.Deadline(config.CF.HTTPServer.RequestTimeout))
 	v1.Use(middlewares.Logger())
 	if config.CF.Swagger.Enable {
 		v1.Get("/swagger/*", swagger.Handler)
 	}
 
+	authentication := v1.Group("auth")
+	authentication.Post("/register", authEndpoint.Register)
+	authentication.Post("/login", authEndpoint.Login)
+	authentication.Post("/refresh", authEndpoint.Refresh)
+	authentication.Post("/logout", middlewares.RequireAuthentication(), authEndpoint.Logout)
+	authentication.Put("/password", middlewares.RequireAuthentication(), authEndpoint.ChangePassword)
+	authentication.Get("/sessions", middlewares.RequireAuthentication(), authEndpoint.ListSessions)
+	authentication.Delete("/sessions", middlewares.RequireAuthentication(), authEndpoint.RevokeAllSessions)
+	authentication.Delete("/sessions/:id", middlewares.RequireAuthentication(), authEndpoint.RevokeSession)
+	authentication.Post("/2fa/enroll", middlewares.RequireAuthentication(), authEndpoint.EnrollTwoFactor)
+	authentication.Post("/2fa/activate", middlewares.RequireAuthentication(), authEndpoint.ActivateTwoFactor)
+	authentication.Post("/2fa/disable", middlewares.RequireAuthentication(), authEndpoint.DisableTwoFactor)
+	authentication.Post("/2fa/verify", authEndpoint.VerifyTwoFactor)
+
+	accountEndpoint := account.NewEndpoint()
+	accounts := v1.Group("account")
+	accounts.Post("/forgot-password", accountEndpoint.ForgotPassword)
+	accounts.Post("/reset-password", accountEndpoint.ResetPassword)
+	accounts.Post("/verify-email", accountEndpoint.VerifyEmail)
+	accounts.Post("/verify-email/resend", middlewares.RequireAuthentication(), accountEndpoint.ResendVerifyEmail)
+
+	otpEndpoint := otp.NewEndpoint()
+	otps := v1.Group("otp")
+	otps.Post("/request", otpEndpoint.Request)
+	otps.Post("/verify", otpEndpoint.Verify)
+
+	apiKeyEndpoint := apikey.NewEndpoint()
+	apiKeys := v1.Group("api-keys", middlewares.RequireAuthentication(), middlewares.RequirePermission("api_keys:write"))
+	apiKeys.Post("/", apiKeyEndpoint.Create)
+	apiKeys.Get("/", apiKeyEndpoint.List)
+	apiKeys.Delete("/:id", apiKeyEndpoint.Revoke)
+
 	userEndpoint := user.NewEndpoint()
-	users := v1.Group("users")
-	users.Get("/:id", handlers.Cache(5*time.Second), userEndpoint.GetUser)
+	users := v1.Group("users", middlewares.RequireAuthenticationOrAPIKey())
+	users.Post("/", middlewares.RequirePermission("users:write"), middlewares.Transaction(sql.Postgres, nil), userEndpoint.CreateUser)
+	users.Get("/", middlewares.RequirePermission("users:read"), userEndpoint.ListUsers)
+	users.Get("/:id", middlewares.RequirePermission("users:read"), userEndpoint.GetUser)
+	users.Put("/:id", middlewares.RequirePermission("users:write"), middlewares.Transaction(sql.Postgres, nil), userEndpoint.UpdateUser)
+	users.Delete("/:id", middlewares.RequirePermission("users:write"), middlewares.Transaction(sql.Postgres, nil), userEndpoint.DeleteUser)
 
-	api.Use(handlers.NotFound("./public/404.html"))
+	auditLogEndpoint := auditlog.NewEndpoint()
+	audits := v1.Group("audit", middlewares.RequireAuthenticationOrAPIKey(), middlewares.RequirePermission("audit:read"))
+	audits.Get("/:entity/:id", auditLogEndpoint.History)
 
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	go func() {
-		<-c
-		_, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	systemEndpoint := system.NewEndpoint()
+	systems := v1.Group("system", middlewares.RequireAuthenticationOrAPIKey(), middlewares.RequirePermission("system:read"))
+	systems.Get("/pool-stats", systemEndpoint.PoolStats)
 
-		logrus.Info("Gracefully shutting down...")
-		_ = app.Shutdown()
-	}()
+	api.Use(handlers.NotFound("./public/404.html"))
 
-	logrus.Infof("Start server on port: %d ...", config.CF.App.Port)
-	err := app.Listen(fmt.Sprintf(":%d", config.CF.App.Port))
-	if err != nil {
-		logrus.Panic(err)
-	}
+	return app
 }
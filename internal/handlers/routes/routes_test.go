@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/sql"
+	"github.com/Thospol/go-fiber/internal/core/utils"
+	"github.com/Thospol/go-fiber/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAPIKeyReachesServiceRoutes(t *testing.T) {
+	if err := config.InitReturnResult("../../../configs"); err != nil {
+		t.Fatalf("init return result: %s", err)
+	}
+
+	database, err := sql.Open(sql.Postgres, config.DatabaseConfig{
+		DriverName:   sql.SQLite,
+		DatabaseName: "file:routes_test?mode=memory&cache=shared",
+		Pool: config.PoolConfig{
+			MaxIdleTime: time.Millisecond,
+			MaxLifetime: time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %s", err)
+	}
+
+	if err := database.AutoMigrate(&models.APIKey{}); err != nil {
+		t.Fatalf("migrate: %s", err)
+	}
+
+	expiredAt := time.Now().Add(-time.Minute)
+	for key, apiKey := range map[string]*models.APIKey{
+		"service-key": {Name: "cron", Scopes: "system:read"},
+		"other-key":   {Name: "partner", Scopes: "users:read"},
+		"expired-key": {Name: "expired", Scopes: "system:read", ExpiresAt: &expiredAt},
+	} {
+		apiKey.KeyHash = utils.SHA256HashHex(key)
+		if err := database.Create(apiKey).Error; err != nil {
+			t.Fatalf("create api key: %s", err)
+		}
+	}
+
+	app := New()
+	for _, tc := range []struct {
+		key    string
+		status int
+	}{
+		{key: "service-key", status: fiber.StatusOK},
+		{key: "other-key", status: fiber.StatusForbidden},
+		{key: "expired-key", status: fiber.StatusUnauthorized},
+		{key: "unknown-key", status: fiber.StatusUnauthorized},
+		{key: "", status: fiber.StatusUnauthorized},
+	} {
+		request := httptest.NewRequest(fiber.MethodGet, "/api/v1/system/pool-stats", nil)
+		if tc.key != "" {
+			request.Header.Set("X-API-Key", tc.key)
+		}
+
+		response, err := app.Test(request)
+		if err != nil {
+			t.Fatalf("%q: %s", tc.key, err)
+		}
+
+		if response.StatusCode != tc.status {
+			t.Errorf("%q: expected status %d, got %d", tc.key, tc.status, response.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package migrations
+
+import "embed"
+
+// FS embedded migrations, one directory per driver (postgres, mysql, sqlite)
+//
+//go:embed postgres/*.sql mysql/*.sql sqlite/*.sql
+var FS embed.FS
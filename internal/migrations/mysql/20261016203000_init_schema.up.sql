@@ -0,0 +1,62 @@
+CREATE TABLE IF NOT EXISTS `users` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` DATETIME(3) NULL,
+    `updated_at` DATETIME(3) NULL,
+    `deleted_at` DATETIME(3) NULL,
+    `version` BIGINT UNSIGNED NOT NULL DEFAULT 1,
+    `pronoun` VARCHAR(191),
+    `name` VARCHAR(191),
+    `role` VARCHAR(191),
+    `email` VARCHAR(191),
+    `phone_number` VARCHAR(191),
+    `password` VARCHAR(255),
+    `email_verified_at` DATETIME(3) NULL,
+    `two_factor_enabled` BOOLEAN NOT NULL DEFAULT FALSE,
+    `two_factor_secret` VARCHAR(255),
+    UNIQUE INDEX `idx_users_email` (`email`),
+    INDEX `idx_users_phone_number` (`phone_number`),
+    INDEX `idx_users_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `api_keys` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` DATETIME(3) NULL,
+    `updated_at` DATETIME(3) NULL,
+    `deleted_at` DATETIME(3) NULL,
+    `name` VARCHAR(191),
+    `prefix` VARCHAR(191),
+    `key_hash` VARCHAR(191),
+    `scopes` TEXT,
+    `expires_at` DATETIME(3) NULL,
+    `last_used_at` DATETIME(3) NULL,
+    INDEX `idx_api_keys_prefix` (`prefix`),
+    UNIQUE INDEX `idx_api_keys_key_hash` (`key_hash`),
+    INDEX `idx_api_keys_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `recovery_codes` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` DATETIME(3) NULL,
+    `updated_at` DATETIME(3) NULL,
+    `deleted_at` DATETIME(3) NULL,
+    `user_id` BIGINT UNSIGNED,
+    `code_hash` VARCHAR(191),
+    `used_at` DATETIME(3) NULL,
+    INDEX `idx_recovery_codes_user_id` (`user_id`),
+    INDEX `idx_recovery_codes_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `audit_records` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `actor_type` VARCHAR(191),
+    `actor_id` VARCHAR(191),
+    `request_id` VARCHAR(191),
+    `entity` VARCHAR(191),
+    `entity_id` VARCHAR(191),
+    `action` VARCHAR(191),
+    `before` LONGTEXT,
+    `after` LONGTEXT,
+    `changes` LONGTEXT,
+    `created_at` DATETIME(3) NULL,
+    INDEX `idx_audit_records_entity` (`entity`, `entity_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS `seed_history` (
+    `name` VARCHAR(191) NOT NULL PRIMARY KEY,
+    `checksum` VARCHAR(64) NOT NULL,
+    `references` LONGTEXT,
+    `applied_at` DATETIME(3) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
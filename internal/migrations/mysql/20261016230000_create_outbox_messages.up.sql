@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `outbox_messages` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `topic` VARCHAR(191) NOT NULL,
+    `payload` LONGTEXT NOT NULL,
+    `status` VARCHAR(191) NOT NULL,
+    `attempts` BIGINT NOT NULL DEFAULT 0,
+    `last_error` LONGTEXT,
+    `next_attempt_at` DATETIME(3) NOT NULL,
+    `created_at` DATETIME(3) NULL,
+    `sent_at` DATETIME(3) NULL,
+    INDEX `idx_outbox_messages_due` (`status`, `next_attempt_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1 @@
+ALTER TABLE `users` DROP INDEX `idx_users_phone_number`, ADD INDEX `idx_users_phone_number` (`phone_number`);
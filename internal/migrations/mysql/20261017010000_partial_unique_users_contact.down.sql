@@ -0,0 +1,9 @@
+ALTER TABLE `users`
+    DROP INDEX `idx_users_lookup_email`,
+    DROP INDEX `idx_users_lookup_phone_number`,
+    DROP INDEX `idx_users_email`,
+    DROP INDEX `idx_users_phone_number`,
+    DROP COLUMN `active_email`,
+    DROP COLUMN `active_phone_number`,
+    ADD UNIQUE INDEX `idx_users_email` (`email`),
+    ADD UNIQUE INDEX `idx_users_phone_number` (`phone_number`);
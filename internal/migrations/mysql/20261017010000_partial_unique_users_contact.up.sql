@@ -0,0 +1,10 @@
+-- mysql has no partial index, unique indexes are on generated columns which are NULL for soft deleted users
+ALTER TABLE `users`
+    ADD COLUMN `active_email` VARCHAR(191) GENERATED ALWAYS AS (IF(`deleted_at` IS NULL, `email`, NULL)) VIRTUAL,
+    ADD COLUMN `active_phone_number` VARCHAR(191) GENERATED ALWAYS AS (IF(`deleted_at` IS NULL, `phone_number`, NULL)) VIRTUAL,
+    DROP INDEX `idx_users_email`,
+    DROP INDEX `idx_users_phone_number`,
+    ADD UNIQUE INDEX `idx_users_email` (`active_email`),
+    ADD UNIQUE INDEX `idx_users_phone_number` (`active_phone_number`),
+    ADD INDEX `idx_users_lookup_email` (`email`),
+    ADD INDEX `idx_users_lookup_phone_number` (`phone_number`);
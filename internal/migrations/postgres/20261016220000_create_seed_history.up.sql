@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS seed_history (
+    name VARCHAR(255) NOT NULL PRIMARY KEY,
+    checksum VARCHAR(64) NOT NULL,
+    "references" TEXT,
+    applied_at TIMESTAMPTZ NOT NULL
+);
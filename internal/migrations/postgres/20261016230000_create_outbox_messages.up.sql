@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id BIGSERIAL PRIMARY KEY,
+    topic TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ,
+    sent_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_due ON outbox_messages (status, next_attempt_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_phone_number;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_number ON users (phone_number);
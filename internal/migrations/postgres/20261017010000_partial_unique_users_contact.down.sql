@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+DROP INDEX IF EXISTS idx_users_phone_number;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_number ON users (phone_number);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS audit_records;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS seed_history (
+    name TEXT NOT NULL PRIMARY KEY,
+    checksum TEXT NOT NULL,
+    "references" TEXT,
+    applied_at DATETIME NOT NULL
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    topic TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at DATETIME NOT NULL,
+    created_at DATETIME,
+    sent_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_due ON outbox_messages (status, next_attempt_at);
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKey api key model for service to service calls
+type APIKey struct {
+	Model
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix" gorm:"index"`
+	KeyHash    string     `json:"-" gorm:"uniqueIndex"`
+	Scopes     string     `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// GetScopes get scopes of api key
+func (key *APIKey) GetScopes() []string {
+	if key.Scopes == "" {
+		return []string{}
+	}
+
+	return strings.Split(key.Scopes, ",")
+}
+
+// SetScopes set scopes of api key
+func (key *APIKey) SetScopes(scopes []string) {
+	key.Scopes = strings.Join(scopes, ",")
+}
+
+// IsExpired check api key is expired
+func (key *APIKey) IsExpired() bool {
+	return key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now())
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// DeviceSession device session of user login
+type DeviceSession struct {
+	ID          string    `json:"id"`
+	UserID      uint      `json:"userId"`
+	AccessUUID  string    `json:"-"`
+	RefreshUUID string    `json:"-"`
+	UserAgent   string    `json:"userAgent"`
+	IP          string    `json:"ip"`
+	CreatedAt   time.Time `json:"createdAt"`
+	LastSeenAt  time.Time `json:"lastSeenAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	TwoFactor   bool      `json:"twoFactor"`
+	Current     bool      `json:"current"`
+}
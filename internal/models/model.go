@@ -8,9 +8,9 @@ import (
 
 // Model common model
 type Model struct {
-	ID        uint           `json:"id,omitempty" gorm:"primary_key"`
-	CreatedAt time.Time      `json:"createdAt,omitempty"`
-	UpdatedAt time.Time      `json:"updatedAt,omitempty"`
+	ID        uint           `json:"id,omitempty" gorm:"primary_key" list:"sort,filter"`
+	CreatedAt time.Time      `json:"createdAt,omitempty" list:"sort,filter"`
+	UpdatedAt time.Time      `json:"updatedAt,omitempty" list:"sort,filter"`
 	DeletedAt gorm.DeletedAt `json:"-" sql:"index"`
 }
 
@@ -23,6 +23,27 @@ type ModelInterface interface {
 	DeleteStamp()
 }
 
+// Versioned optional version field for optimistic concurrency control
+type Versioned struct {
+	Version uint `json:"version" gorm:"not null;default:1"`
+}
+
+// VersionInterface versioned model interface
+type VersionInterface interface {
+	GetVersion() uint
+	SetVersion(version uint)
+}
+
+// GetVersion get version
+func (v *Versioned) GetVersion() uint {
+	return v.Version
+}
+
+// SetVersion set version
+func (v *Versioned) SetVersion(version uint) {
+	v.Version = version
+}
+
 // GetID get id
 func (model *Model) GetID() uint {
 	return model.ID
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// RecoveryCode recovery code of two factor authentication
+type RecoveryCode struct {
+	Model
+	UserID   uint       `json:"userId" gorm:"index"`
+	CodeHash string     `json:"-"`
+	UsedAt   *time.Time `json:"usedAt,omitempty"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// ServiceSession service session of api key caller
+type ServiceSession struct {
+	APIKeyID uint     `json:"apiKeyId"`
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+}
+
+// HasScope check service session has scope, support wildcard `*` and `resource:*`
+func (ss *ServiceSession) HasScope(scope string) bool {
+	return MatchPermission(ss.Scopes, scope)
+}
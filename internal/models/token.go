@@ -0,0 +1,10 @@
+package models
+
+// Token token pair model
+type Token struct {
+	AccessToken       string `json:"accessToken,omitempty"`
+	RefreshToken      string `json:"refreshToken,omitempty"`
+	ExpiresIn         int64  `json:"expiresIn,omitempty"`
+	TwoFactorRequired bool   `json:"twoFactorRequired,omitempty"`
+	TwoFactorToken    string `json:"twoFactorToken,omitempty"`
+}
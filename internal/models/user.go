@@ -1,8 +1,26 @@
 package models
 
+import "time"
+
+const (
+	// RoleAdmin role admin
+	RoleAdmin = "admin"
+	// RoleUser role user
+	RoleUser = "user"
+)
+
 // User user model
 type User struct {
 	Model
-	Pronoun string `json:"pronoun"`
-	Name    string `json:"name"`
+	Versioned
+	Pronoun         string     `json:"pronoun"`
+	Name            string     `json:"name" list:"sort,filter"`
+	Role            string     `json:"role" list:"filter"`
+	Email           string     `json:"email" gorm:"uniqueIndex:idx_users_email,where:deleted_at IS NULL" list:"sort,filter"`
+	PhoneNumber     string     `json:"phoneNumber" gorm:"uniqueIndex:idx_users_phone_number,where:deleted_at IS NULL" list:"filter"`
+	Password        string     `json:"-"`
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt,omitempty"`
+
+	TwoFactorEnabled bool   `json:"twoFactorEnabled"`
+	TwoFactorSecret  string `json:"-"`
 }
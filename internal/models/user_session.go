@@ -1,8 +1,35 @@
 package models
 
+import "strings"
+
 // UserSession user session
 type UserSession struct {
-	Id          uint   `json:"userId"`
-	AccessUUID  string `json:"accessUUID"`
-	RefreshUUID string `json:"refreshUUID"`
+	Id          uint     `json:"userId"`
+	SessionID   string   `json:"sessionId"`
+	AccessUUID  string   `json:"accessUUID"`
+	RefreshUUID string   `json:"refreshUUID"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+	TwoFactor   bool     `json:"twoFactor"`
+}
+
+// HasPermission check user session has permission, support wildcard `*` and `resource:*`
+func (us *UserSession) HasPermission(permission string) bool {
+	return MatchPermission(us.Permissions, permission)
+}
+
+// MatchPermission check granted permissions (permissions of role or scopes of api key) match permission,
+// support wildcard `*` and `resource:*`
+func MatchPermission(granted []string, permission string) bool {
+	for _, p := range granted {
+		if p == "*" || p == permission {
+			return true
+		}
+
+		if strings.HasSuffix(p, ":*") && strings.HasPrefix(permission, strings.TrimSuffix(p, "*")) {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,156 @@
+package account
+
+import (
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/context"
+	"github.com/Thospol/go-fiber/internal/core/render"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Endpoint account endpoint interface
+type Endpoint interface {
+	ForgotPassword(c *fiber.Ctx) error
+	ResetPassword(c *fiber.Ctx) error
+	ResendVerifyEmail(c *fiber.Ctx) error
+	VerifyEmail(c *fiber.Ctx) error
+}
+
+type endpoint struct {
+	config  *config.Configs
+	result  *config.ReturnResult
+	service Service
+}
+
+// NewEndpoint new account endpoint
+func NewEndpoint() Endpoint {
+	return &endpoint{
+		config:  config.CF,
+		result:  config.RR,
+		service: NewService(),
+	}
+}
+
+// ForgotPassword godoc
+// @Tags Account
+// @Summary ForgotPassword
+// @Description Request send reset password link to email, success is returned for unregistered email too
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body forgotPasswordRequest true "request body"
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 429 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Router /account/forgot-password [post]
+func (ep *endpoint) ForgotPassword(c *fiber.Ctx) error {
+	request := new(forgotPasswordRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[ForgotPassword] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+	request.IP = c.IP()
+
+	lang, _ := c.Locals(context.LangKey).(string)
+	err = ep.service.ForgotPassword(ctx.GetPostgreDatabase(), request, lang)
+	if err != nil {
+		logrus.Errorf("[ForgotPassword] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
+
+// ResetPassword godoc
+// @Tags Account
+// @Summary ResetPassword
+// @Description Request reset password with token, all device sessions are revoked
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body resetPasswordRequest true "request body"
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Router /account/reset-password [post]
+func (ep *endpoint) ResetPassword(c *fiber.Ctx) error {
+	request := new(resetPasswordRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[ResetPassword] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	err = ep.service.ResetPassword(ctx.GetPostgreDatabase(), request)
+	if err != nil {
+		logrus.Errorf("[ResetPassword] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
+
+// ResendVerifyEmail godoc
+// @Tags Account
+// @Summary ResendVerifyEmail
+// @Description Request send verify email link to email of current user
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /account/verify-email/resend [post]
+func (ep *endpoint) ResendVerifyEmail(c *fiber.Ctx) error {
+	ctx := context.New(c)
+	user, err := ctx.GetUser()
+	if err != nil {
+		logrus.Errorf("[ResendVerifyEmail] get user error: %s", err)
+		return render.Error(c, err)
+	}
+
+	lang, _ := c.Locals(context.LangKey).(string)
+	err = ep.service.ResendVerifyEmail(ctx.GetPostgreDatabase(), user, lang)
+	if err != nil {
+		logrus.Errorf("[ResendVerifyEmail] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
+
+// VerifyEmail godoc
+// @Tags Account
+// @Summary VerifyEmail
+// @Description Request verify email with token
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body verifyEmailRequest true "request body"
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Router /account/verify-email [post]
+func (ep *endpoint) VerifyEmail(c *fiber.Ctx) error {
+	request := new(verifyEmailRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[VerifyEmail] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	err = ep.service.VerifyEmail(ctx.GetPostgreDatabase(), request)
+	if err != nil {
+		logrus.Errorf("[VerifyEmail] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
@@ -0,0 +1,36 @@
+package account
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/notification"
+)
+
+func resetPasswordMessage(link string, expire time.Duration, lang string) notification.Message {
+	if lang == "th" {
+		return notification.Message{
+			Subject: "ตั้งรหัสผ่านใหม่",
+			Body:    fmt.Sprintf("กรุณาคลิกลิงก์ต่อไปนี้เพื่อตั้งรหัสผ่านใหม่ ลิงก์มีอายุการใช้งาน %d นาที\n\n%s\n\nหากคุณไม่ได้ร้องขอ กรุณาเพิกเฉยอีเมลฉบับนี้", int(expire.Minutes()), link),
+		}
+	}
+
+	return notification.Message{
+		Subject: "Reset your password",
+		Body:    fmt.Sprintf("Please click the link below to reset your password. The link expires in %d minutes\n\n%s\n\nIf you did not request this, please ignore this email", int(expire.Minutes()), link),
+	}
+}
+
+func verifyEmailMessage(link string, expire time.Duration, lang string) notification.Message {
+	if lang == "th" {
+		return notification.Message{
+			Subject: "ยืนยันอีเมล",
+			Body:    fmt.Sprintf("กรุณาคลิกลิงก์ต่อไปนี้เพื่อยืนยันอีเมลของคุณ ลิงก์มีอายุการใช้งาน %d ชั่วโมง\n\n%s", int(expire.Hours()), link),
+		}
+	}
+
+	return notification.Message{
+		Subject: "Verify your email",
+		Body:    fmt.Sprintf("Please click the link below to verify your email. The link expires in %d hours\n\n%s", int(expire.Hours()), link),
+	}
+}
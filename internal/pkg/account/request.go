@@ -0,0 +1,16 @@
+package account
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" validate:"required"`
+	IP    string `json:"-"`
+}
+
+type resetPasswordRequest struct {
+	Token           string `json:"token" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	ConfirmPassword string `json:"confirmPassword" validate:"required"`
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
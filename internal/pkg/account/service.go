@@ -0,0 +1,228 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/notification"
+	"github.com/Thospol/go-fiber/internal/core/password"
+	"github.com/Thospol/go-fiber/internal/core/redis"
+	"github.com/Thospol/go-fiber/internal/core/session"
+	"github.com/Thospol/go-fiber/internal/core/utils"
+	"github.com/Thospol/go-fiber/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	resetPasswordKey          = "reset_password:%s"
+	verifyEmailKey            = "verify_email:%s"
+	forgotPasswordCooldownKey = "forgot_password_cooldown:%s"
+	forgotPasswordIPKey       = "forgot_password_ip:%s"
+
+	tokenLength = 32
+)
+
+// Service account service interface
+type Service interface {
+	ForgotPassword(database *gorm.DB, request *forgotPasswordRequest, lang string) error
+	ResetPassword(database *gorm.DB, request *resetPasswordRequest) error
+	SendVerifyEmail(ctx context.Context, user *models.User, lang string) error
+	ResendVerifyEmail(database *gorm.DB, user *models.UserSession, lang string) error
+	VerifyEmail(database *gorm.DB, request *verifyEmailRequest) error
+}
+
+type service struct {
+	config *config.Configs
+	result *config.ReturnResult
+	mailer notification.Sender
+}
+
+// NewService new account service
+func NewService() Service {
+	return &service{
+		config: config.CF,
+		result: config.RR,
+		mailer: notification.NewEmailSender(config.CF.Notification.Email),
+	}
+}
+
+// ForgotPassword send reset password link to email, requests are limited per email and per ip,
+// unknown email succeeds without sending, so registered emails cannot be discovered
+func (s *service) ForgotPassword(database *gorm.DB, request *forgotPasswordRequest, lang string) error {
+	if !utils.IsValidEmail(request.Email) {
+		return s.result.InvalidEmail
+	}
+
+	if err := s.limitForgotPassword(database.Statement.Context, request.Email, request.IP); err != nil {
+		return err
+	}
+
+	user := &models.User{}
+	err := database.Where("email = ?", request.Email).First(user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.Info("[ForgotPassword] email is not registered, reset password link is not sent")
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.createToken(database.Statement.Context, resetPasswordKey, user.ID, s.config.Account.ResetPasswordExpireTime)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.config.App.WebBaseURL, token)
+	return s.mailer.Send(user.Email, resetPasswordMessage(link, s.config.Account.ResetPasswordExpireTime, lang))
+}
+
+// ResetPassword reset password by token and revoke all device sessions of user
+func (s *service) ResetPassword(database *gorm.DB, request *resetPasswordRequest) error {
+	err := password.Validate(request.Password, request.ConfirmPassword)
+	if err != nil {
+		return err
+	}
+
+	userID, err := s.useToken(database.Statement.Context, resetPasswordKey, request.Token)
+	if err != nil {
+		return err
+	}
+
+	hash, err := password.Hash(request.Password)
+	if err != nil {
+		return err
+	}
+
+	result := database.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"password": hash,
+		"version":  gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return s.result.InvalidToken
+	}
+
+	return session.RevokeAll(database.Statement.Context, userID)
+}
+
+// SendVerifyEmail send verify email link to email of user
+func (s *service) SendVerifyEmail(ctx context.Context, user *models.User, lang string) error {
+	token, err := s.createToken(ctx, verifyEmailKey, user.ID, s.config.Account.VerifyEmailExpireTime)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.config.App.WebBaseURL, token)
+	return s.mailer.Send(user.Email, verifyEmailMessage(link, s.config.Account.VerifyEmailExpireTime, lang))
+}
+
+// ResendVerifyEmail send verify email link to email of user session
+func (s *service) ResendVerifyEmail(database *gorm.DB, user *models.UserSession, lang string) error {
+	entity := &models.User{}
+	err := database.First(entity, user.Id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.result.Internal.DatabaseNotFound
+		}
+		return err
+	}
+
+	if entity.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	return s.SendVerifyEmail(database.Statement.Context, entity, lang)
+}
+
+// VerifyEmail mark email of user as verified by token
+func (s *service) VerifyEmail(database *gorm.DB, request *verifyEmailRequest) error {
+	userID, err := s.useToken(database.Statement.Context, verifyEmailKey, request.Token)
+	if err != nil {
+		return err
+	}
+
+	result := database.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"email_verified_at": time.Now(),
+		"version":           gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return s.result.InvalidToken
+	}
+
+	return nil
+}
+
+// createToken create single use token, only hash of token is stored on redis
+func (s *service) createToken(ctx context.Context, keyFormat string, userID uint, expiredTime time.Duration) (string, error) {
+	b := make([]byte, tokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	err := redis.GetConnection().Set(ctx, fmt.Sprintf(keyFormat, utils.SHA256HashHex(token)), userID, expiredTime)
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// useToken get user id of token and delete token atomically, so token is redeemed once
+func (s *service) useToken(ctx context.Context, keyFormat string, token string) (uint, error) {
+	key := fmt.Sprintf(keyFormat, utils.SHA256HashHex(token))
+
+	var userID uint
+	err := redis.GetConnection().GetDelete(ctx, key, &userID)
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return 0, s.result.InvalidToken
+		}
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// limitForgotPassword limit forgot password requests of email by interval and of ip by max requests in window
+func (s *service) limitForgotPassword(ctx context.Context, email, ip string) error {
+	client := redis.GetConnection()
+	if s.config.Account.ForgotPasswordMaxIPRequests > 0 && ip != "" {
+		requests, err := client.Incr(ctx, fmt.Sprintf(forgotPasswordIPKey, ip), s.config.Account.ForgotPasswordWindow)
+		if err != nil {
+			return err
+		}
+
+		if requests > s.config.Account.ForgotPasswordMaxIPRequests {
+			return s.result.Internal.TooManyRequests
+		}
+	}
+
+	if s.config.Account.ForgotPasswordInterval > 0 {
+		requests, err := client.Incr(ctx, fmt.Sprintf(forgotPasswordCooldownKey, utils.SHA256HashHex(strings.ToLower(email))), s.config.Account.ForgotPasswordInterval)
+		if err != nil {
+			return err
+		}
+
+		if requests > 1 {
+			return s.result.Internal.TooManyRequests
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package account
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/notification"
+	"github.com/Thospol/go-fiber/internal/core/redis"
+	"github.com/Thospol/go-fiber/internal/core/redis/redistest"
+	"github.com/Thospol/go-fiber/internal/models"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// recipients recipients of sent messages
+type recipients []string
+
+func (r *recipients) Send(recipient string, _ notification.Message) error {
+	*r = append(*r, recipient)
+	return nil
+}
+
+func TestForgotPasswordDoesNotDiscloseEmail(t *testing.T) {
+	server := redistest.New(t)
+	host, port := server.HostPort()
+	if err := redis.Init(redis.Configuration{Host: host, Port: port}); err != nil {
+		t.Fatalf("init redis: %s", err)
+	}
+	t.Cleanup(redis.GetConnection().Close)
+
+	database, err := gorm.Open(sqlite.Dialector{DriverName: "sqlite", DSN: filepath.Join(t.TempDir(), "account.db")}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open: %s", err)
+	}
+
+	if err := database.AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("migrate: %s", err)
+	}
+
+	if err := database.Create(&models.User{Name: "User", Email: "user@example.com"}).Error; err != nil {
+		t.Fatalf("create user: %s", err)
+	}
+
+	cf := &config.Configs{}
+	cf.Account.ResetPasswordExpireTime = time.Hour
+	cf.Account.ForgotPasswordInterval = time.Minute
+
+	rr := &config.ReturnResult{}
+	rr.Internal.TooManyRequests = config.Result{Code: 429}
+
+	sent := &recipients{}
+	s := &service{config: cf, result: rr, mailer: sent}
+	database = database.WithContext(context.Background())
+
+	for _, email := range []string{"user@example.com", "unknown@example.com"} {
+		if err := s.ForgotPassword(database, &forgotPasswordRequest{Email: email}, "en"); err != nil {
+			t.Fatalf("%s: expected success, got %v", email, err)
+		}
+
+		if err := s.ForgotPassword(database, &forgotPasswordRequest{Email: email}, "en"); err != rr.Internal.TooManyRequests {
+			t.Fatalf("%s: expected too many requests in interval, got %v", email, err)
+		}
+	}
+
+	if len(*sent) != 1 || (*sent)[0] != "user@example.com" {
+		t.Fatalf("expected link sent to registered email only, got %v", *sent)
+	}
+}
@@ -0,0 +1,118 @@
+package apikey
+
+import (
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/context"
+	"github.com/Thospol/go-fiber/internal/core/render"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Endpoint api key endpoint interface
+type Endpoint interface {
+	Create(c *fiber.Ctx) error
+	List(c *fiber.Ctx) error
+	Revoke(c *fiber.Ctx) error
+}
+
+type endpoint struct {
+	config  *config.Configs
+	result  *config.ReturnResult
+	service Service
+}
+
+// NewEndpoint new api key endpoint
+func NewEndpoint() Endpoint {
+	return &endpoint{
+		config:  config.CF,
+		result:  config.RR,
+		service: NewService(),
+	}
+}
+
+// Create godoc
+// @Tags APIKey
+// @Summary Create
+// @Description Request create api key, the key is shown only once
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body createAPIKeyRequest true "request body"
+// @Success 200 {object} createAPIKeyResponse
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /api-keys [post]
+func (ep *endpoint) Create(c *fiber.Ctx) error {
+	request := new(createAPIKeyRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[Create] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	response, err := ep.service.Create(ctx.GetPostgreDatabase(), request)
+	if err != nil {
+		logrus.Errorf("[Create] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// List godoc
+// @Tags APIKey
+// @Summary List
+// @Description Request list api keys
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Success 200 {array} models.APIKey
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /api-keys [get]
+func (ep *endpoint) List(c *fiber.Ctx) error {
+	ctx := context.New(c)
+	response, err := ep.service.List(ctx.GetPostgreDatabase())
+	if err != nil {
+		logrus.Errorf("[List] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// Revoke godoc
+// @Tags APIKey
+// @Summary Revoke
+// @Description Request revoke api key by id
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param id path string true "input id" default(1)
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 404 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /api-keys/{id} [delete]
+func (ep *endpoint) Revoke(c *fiber.Ctx) error {
+	request := new(revokeAPIKeyRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, false)
+	if err != nil {
+		logrus.Errorf("[Revoke] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	err = ep.service.Revoke(ctx.GetPostgreDatabase(), request)
+	if err != nil {
+		logrus.Errorf("[Revoke] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
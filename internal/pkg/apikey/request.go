@@ -0,0 +1,13 @@
+package apikey
+
+import "time"
+
+type createAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,maxString=255"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type revokeAPIKeyRequest struct {
+	Id uint `form:"id" json:"id" path:"id" query:"id" xml:"id"`
+}
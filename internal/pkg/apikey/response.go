@@ -0,0 +1,8 @@
+package apikey
+
+import "github.com/Thospol/go-fiber/internal/models"
+
+type createAPIKeyResponse struct {
+	*models.APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,109 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/utils"
+	"github.com/Thospol/go-fiber/internal/models"
+	"github.com/Thospol/go-fiber/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	prefixLength = 4
+	secretLength = 32
+)
+
+// Service api key service interface
+type Service interface {
+	Create(database *gorm.DB, request *createAPIKeyRequest) (*createAPIKeyResponse, error)
+	List(database *gorm.DB) ([]*models.APIKey, error)
+	Revoke(database *gorm.DB, request *revokeAPIKeyRequest) error
+}
+
+type service struct {
+	config     *config.Configs
+	result     *config.ReturnResult
+	repository repositories.Repository
+}
+
+// NewService new api key service
+func NewService() Service {
+	return &service{
+		config:     config.CF,
+		result:     config.RR,
+		repository: repositories.NewRepository(),
+	}
+}
+
+// Create create api key, the plain key is returned only once
+func (s *service) Create(database *gorm.DB, request *createAPIKeyRequest) (*createAPIKeyResponse, error) {
+	prefix, err := randomBytes(prefixLength)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := randomBytes(secretLength)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s.%s", hex.EncodeToString(prefix), base64.RawURLEncoding.EncodeToString(secret))
+	apiKey := &models.APIKey{
+		Name:      request.Name,
+		Prefix:    hex.EncodeToString(prefix),
+		KeyHash:   utils.SHA256HashHex(key),
+		ExpiresAt: request.ExpiresAt,
+	}
+	apiKey.SetScopes(utils.UniqueSliceString(utils.TrimSpaces(request.Scopes)))
+
+	err = s.repository.Create(database, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createAPIKeyResponse{
+		APIKey: apiKey,
+		Key:    key,
+	}, nil
+}
+
+// List list api keys
+func (s *service) List(database *gorm.DB) ([]*models.APIKey, error) {
+	apiKeys := []*models.APIKey{}
+	err := database.Order("id desc").Find(&apiKeys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
+
+// Revoke revoke api key
+func (s *service) Revoke(database *gorm.DB, request *revokeAPIKeyRequest) error {
+	apiKey := &models.APIKey{}
+	err := s.repository.FindByID(database, request.Id, apiKey)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.result.Internal.DatabaseNotFound
+		}
+		return err
+	}
+
+	return s.repository.Delete(database, apiKey)
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
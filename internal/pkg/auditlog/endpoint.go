@@ -0,0 +1,69 @@
+package auditlog
+
+import (
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/context"
+	"github.com/Thospol/go-fiber/internal/core/render"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Endpoint audit log endpoint interface
+type Endpoint interface {
+	History(c *fiber.Ctx) error
+}
+
+type endpoint struct {
+	config  *config.Configs
+	result  *config.ReturnResult
+	service Service
+}
+
+// NewEndpoint new audit log endpoint
+func NewEndpoint() Endpoint {
+	return &endpoint{
+		config:  config.CF,
+		result:  config.RR,
+		service: NewService(),
+	}
+}
+
+// History godoc
+// @Tags Audit
+// @Summary History
+// @Description Request history of changes of entity (table or collection name), latest first, filter by `actorType, actorId, requestId, action, createdAt`
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param entity path string true "table or collection name" default(users)
+// @Param id path string true "entity id" default(1)
+// @Param page query int false "page" default(1)
+// @Param limit query int false "limit" default(20)
+// @Param sort query string false "sort e.g. createdAt"
+// @Param filter query string false "filter e.g. action:eq:update"
+// @Success 200 {object} query.Result
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 403 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Security ServiceKeyAuth
+// @Router /audit/{entity}/{id} [get]
+func (ep *endpoint) History(c *fiber.Ctx) error {
+	request := new(historyRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, false)
+	if err != nil {
+		logrus.Errorf("[History] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	response, err := ep.service.History(ctx.RequestContext(), request)
+	if err != nil {
+		logrus.Errorf("[History] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, response)
+}
@@ -0,0 +1,9 @@
+package auditlog
+
+import "github.com/Thospol/go-fiber/internal/core/query"
+
+type historyRequest struct {
+	query.Query
+	Entity string `form:"entity" json:"entity" path:"entity" query:"entity" xml:"entity"`
+	Id     string `form:"id" json:"id" path:"id" query:"id" xml:"id"`
+}
@@ -0,0 +1,42 @@
+package auditlog
+
+import (
+	"context"
+
+	"github.com/Thospol/go-fiber/internal/core/audit"
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/query"
+)
+
+// Service audit log service interface
+type Service interface {
+	History(ctx context.Context, request *historyRequest) (*query.Result, error)
+}
+
+type service struct {
+	config *config.Configs
+	result *config.ReturnResult
+}
+
+// NewService new audit log service
+func NewService() Service {
+	return &service{
+		config: config.CF,
+		result: config.RR,
+	}
+}
+
+// History history of changes of entity
+func (s *service) History(ctx context.Context, request *historyRequest) (*query.Result, error) {
+	store := audit.GetStore()
+	if store == nil {
+		return &query.Result{Data: []*audit.Record{}, Meta: request.Meta(0)}, nil
+	}
+
+	records, meta, err := store.History(ctx, request.Entity, request.Id, &request.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &query.Result{Data: records, Meta: meta}, nil
+}
@@ -0,0 +1,472 @@
+package auth
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/context"
+	"github.com/Thospol/go-fiber/internal/core/jwt"
+	"github.com/Thospol/go-fiber/internal/core/render"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Endpoint auth endpoint interface
+type Endpoint interface {
+	Register(c *fiber.Ctx) error
+	Login(c *fiber.Ctx) error
+	Refresh(c *fiber.Ctx) error
+	Logout(c *fiber.Ctx) error
+	ListSessions(c *fiber.Ctx) error
+	RevokeSession(c *fiber.Ctx) error
+	RevokeAllSessions(c *fiber.Ctx) error
+	ChangePassword(c *fiber.Ctx) error
+	EnrollTwoFactor(c *fiber.Ctx) error
+	ActivateTwoFactor(c *fiber.Ctx) error
+	DisableTwoFactor(c *fiber.Ctx) error
+	VerifyTwoFactor(c *fiber.Ctx) error
+	JWKS(c *fiber.Ctx) error
+}
+
+type endpoint struct {
+	config  *config.Configs
+	result  *config.ReturnResult
+	service Service
+}
+
+// NewEndpoint new auth endpoint
+func NewEndpoint() Endpoint {
+	return &endpoint{
+		config:  config.CF,
+		result:  config.RR,
+		service: NewService(),
+	}
+}
+
+// Register godoc
+// @Tags Auth
+// @Summary Register
+// @Description Request register user with email, phone number and password
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body registerRequest true "request body"
+// @Success 200 {object} models.User
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Router /auth/register [post]
+func (ep *endpoint) Register(c *fiber.Ctx) error {
+	request := new(registerRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[Register] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	lang, _ := c.Locals(context.LangKey).(string)
+	response, err := ep.service.Register(ctx.GetPostgreDatabase(), request, lang)
+	if err != nil {
+		logrus.Errorf("[Register] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// Login godoc
+// @Tags Auth
+// @Summary Login
+// @Description Request login with email and password, otp is required after too many failed attempts when login protection action is otp
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body loginRequest true "request body"
+// @Success 200 {object} models.Token
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Failure 429 {object} config.SwaggerInfoResult
+// @Router /auth/login [post]
+func (ep *endpoint) Login(c *fiber.Ctx) error {
+	request := new(loginRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[Login] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	request.UserAgent = c.Get(fiber.HeaderUserAgent)
+	request.IP = c.IP()
+
+	response, err := ep.service.Login(ctx.GetPostgreDatabase(), request)
+	if err != nil {
+		logrus.Errorf("[Login] call service error: %s", err)
+		return renderError(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// Refresh godoc
+// @Tags Auth
+// @Summary Refresh
+// @Description Request new token pair with refresh token
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body refreshRequest true "request body"
+// @Success 200 {object} models.Token
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Router /auth/refresh [post]
+func (ep *endpoint) Refresh(c *fiber.Ctx) error {
+	request := new(refreshRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[Refresh] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	request.UserAgent = c.Get(fiber.HeaderUserAgent)
+	request.IP = c.IP()
+
+	response, err := ep.service.Refresh(ctx.GetPostgreDatabase(), request)
+	if err != nil {
+		logrus.Errorf("[Refresh] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// Logout godoc
+// @Tags Auth
+// @Summary Logout
+// @Description Request revoke current token pair
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /auth/logout [post]
+func (ep *endpoint) Logout(c *fiber.Ctx) error {
+	ctx := context.New(c)
+	user, err := ctx.GetUser()
+	if err != nil {
+		logrus.Errorf("[Logout] get user error: %s", err)
+		return render.Error(c, err)
+	}
+
+	err = ep.service.Logout(ctx.RequestContext(), user)
+	if err != nil {
+		logrus.Errorf("[Logout] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
+
+// ListSessions godoc
+// @Tags Auth
+// @Summary ListSessions
+// @Description Request list active device sessions of current user
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Success 200 {array} models.DeviceSession
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /auth/sessions [get]
+func (ep *endpoint) ListSessions(c *fiber.Ctx) error {
+	ctx := context.New(c)
+	user, err := ctx.GetUser()
+	if err != nil {
+		logrus.Errorf("[ListSessions] get user error: %s", err)
+		return render.Error(c, err)
+	}
+
+	response, err := ep.service.ListSessions(ctx.RequestContext(), user)
+	if err != nil {
+		logrus.Errorf("[ListSessions] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// RevokeSession godoc
+// @Tags Auth
+// @Summary RevokeSession
+// @Description Request revoke device session of current user by id
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param id path string true "input session id"
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 404 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /auth/sessions/{id} [delete]
+func (ep *endpoint) RevokeSession(c *fiber.Ctx) error {
+	request := new(revokeSessionRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, false)
+	if err != nil {
+		logrus.Errorf("[RevokeSession] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	user, err := ctx.GetUser()
+	if err != nil {
+		logrus.Errorf("[RevokeSession] get user error: %s", err)
+		return render.Error(c, err)
+	}
+
+	err = ep.service.RevokeSession(ctx.RequestContext(), user, request)
+	if err != nil {
+		logrus.Errorf("[RevokeSession] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
+
+// RevokeAllSessions godoc
+// @Tags Auth
+// @Summary RevokeAllSessions
+// @Description Request revoke all device sessions of current user (log out everywhere)
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /auth/sessions [delete]
+func (ep *endpoint) RevokeAllSessions(c *fiber.Ctx) error {
+	ctx := context.New(c)
+	user, err := ctx.GetUser()
+	if err != nil {
+		logrus.Errorf("[RevokeAllSessions] get user error: %s", err)
+		return render.Error(c, err)
+	}
+
+	err = ep.service.RevokeAllSessions(ctx.RequestContext(), user)
+	if err != nil {
+		logrus.Errorf("[RevokeAllSessions] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
+
+// ChangePassword godoc
+// @Tags Auth
+// @Summary ChangePassword
+// @Description Request change password, all device sessions are revoked
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body changePasswordRequest true "request body"
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /auth/password [put]
+func (ep *endpoint) ChangePassword(c *fiber.Ctx) error {
+	request := new(changePasswordRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[ChangePassword] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	user, err := ctx.GetUser()
+	if err != nil {
+		logrus.Errorf("[ChangePassword] get user error: %s", err)
+		return render.Error(c, err)
+	}
+
+	err = ep.service.ChangePassword(ctx.GetPostgreDatabase(), user, request)
+	if err != nil {
+		logrus.Errorf("[ChangePassword] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
+
+// EnrollTwoFactor godoc
+// @Tags Auth
+// @Summary EnrollTwoFactor
+// @Description Request totp secret and provisioning uri for authenticator app
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Success 200 {object} enrollTwoFactorResponse
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /auth/2fa/enroll [post]
+func (ep *endpoint) EnrollTwoFactor(c *fiber.Ctx) error {
+	ctx := context.New(c)
+	user, err := ctx.GetUser()
+	if err != nil {
+		logrus.Errorf("[EnrollTwoFactor] get user error: %s", err)
+		return render.Error(c, err)
+	}
+
+	response, err := ep.service.EnrollTwoFactor(ctx.GetPostgreDatabase(), user)
+	if err != nil {
+		logrus.Errorf("[EnrollTwoFactor] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// ActivateTwoFactor godoc
+// @Tags Auth
+// @Summary ActivateTwoFactor
+// @Description Request activate two factor with totp code, recovery codes are shown only once
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body twoFactorCodeRequest true "request body"
+// @Success 200 {object} recoveryCodesResponse
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Failure 429 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /auth/2fa/activate [post]
+func (ep *endpoint) ActivateTwoFactor(c *fiber.Ctx) error {
+	request := new(twoFactorCodeRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[ActivateTwoFactor] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	user, err := ctx.GetUser()
+	if err != nil {
+		logrus.Errorf("[ActivateTwoFactor] get user error: %s", err)
+		return render.Error(c, err)
+	}
+
+	response, err := ep.service.ActivateTwoFactor(ctx.GetPostgreDatabase(), user, request)
+	if err != nil {
+		logrus.Errorf("[ActivateTwoFactor] call service error: %s", err)
+		return renderError(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// DisableTwoFactor godoc
+// @Tags Auth
+// @Summary DisableTwoFactor
+// @Description Request disable two factor with totp or recovery code
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body twoFactorCodeRequest true "request body"
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Failure 429 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Router /auth/2fa/disable [post]
+func (ep *endpoint) DisableTwoFactor(c *fiber.Ctx) error {
+	request := new(twoFactorCodeRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[DisableTwoFactor] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	user, err := ctx.GetUser()
+	if err != nil {
+		logrus.Errorf("[DisableTwoFactor] get user error: %s", err)
+		return render.Error(c, err)
+	}
+
+	err = ep.service.DisableTwoFactor(ctx.GetPostgreDatabase(), user, request)
+	if err != nil {
+		logrus.Errorf("[DisableTwoFactor] call service error: %s", err)
+		return renderError(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
+
+// VerifyTwoFactor godoc
+// @Tags Auth
+// @Summary VerifyTwoFactor
+// @Description Request token pair with two factor token from login and totp or recovery code
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body verifyTwoFactorRequest true "request body"
+// @Success 200 {object} models.Token
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Failure 429 {object} config.SwaggerInfoResult
+// @Router /auth/2fa/verify [post]
+func (ep *endpoint) VerifyTwoFactor(c *fiber.Ctx) error {
+	request := new(verifyTwoFactorRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[VerifyTwoFactor] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	request.UserAgent = c.Get(fiber.HeaderUserAgent)
+	request.IP = c.IP()
+
+	response, err := ep.service.VerifyTwoFactor(ctx.GetPostgreDatabase(), request)
+	if err != nil {
+		logrus.Errorf("[VerifyTwoFactor] call service error: %s", err)
+		return renderError(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// JWKS godoc
+// @Tags Auth
+// @Summary JWKS
+// @Description Request public keys for verify token
+// @Produce json
+// @Success 200 {object} jwt.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func (ep *endpoint) JWKS(c *fiber.Ctx) error {
+	return render.JSON(c, jwt.JWKS())
+}
+
+// renderError render error to client, Retry-After header is set when account is locked
+func renderError(c *fiber.Ctx, err error) error {
+	var locked *accountLockedError
+	if errors.As(err, &locked) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		return render.Error(c, locked.Result)
+	}
+
+	return render.Error(c, err)
+}
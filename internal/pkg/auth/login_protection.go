@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/redis"
+	"github.com/Thospol/go-fiber/internal/pkg/otp"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// LoginProtectionLock lock account after max failed attempts
+	LoginProtectionLock = "lock"
+	// LoginProtectionOTP require email otp after max failed attempts
+	LoginProtectionOTP = "otp"
+
+	loginAttemptsAccountKey = "login_attempts_account:%s"
+	loginAttemptsIPKey      = "login_attempts_ip:%s"
+	loginLockAccountKey     = "login_lock_account:%s"
+	loginLockIPKey          = "login_lock_ip:%s"
+)
+
+// accountLockedError login is locked until retry after
+type accountLockedError struct {
+	config.Result
+	RetryAfter time.Duration
+}
+
+// newAccountLockedError new account locked error
+func newAccountLockedError(result *config.ReturnResult, until time.Time) *accountLockedError {
+	retryAfter := time.Until(until)
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+
+	return &accountLockedError{
+		Result:     result.AccountTemporarilyLocked.WithArgs(int(math.Ceil(retryAfter.Minutes()))),
+		RetryAfter: retryAfter,
+	}
+}
+
+// checkLoginLocked check ip and account are not locked
+func (s *service) checkLoginLocked(ctx context.Context, email, ip string) error {
+	client := redis.GetConnection()
+	for _, key := range []string{
+		fmt.Sprintf(loginLockIPKey, ip),
+		fmt.Sprintf(loginLockAccountKey, strings.ToLower(email)),
+	} {
+		var until time.Time
+		if err := client.Get(ctx, key, &until); err == nil && time.Now().Before(until) {
+			return newAccountLockedError(s.result, until)
+		}
+	}
+
+	return nil
+}
+
+// checkLoginOtp require valid email otp when account reached max failed attempts
+func (s *service) checkLoginOtp(ctx context.Context, request *loginRequest) error {
+	if s.config.LoginProtection.Action != LoginProtectionOTP || s.config.LoginProtection.MaxAccountAttempts <= 0 {
+		return nil
+	}
+
+	attempts, err := redis.GetConnection().GetInt(ctx, fmt.Sprintf(loginAttemptsAccountKey, strings.ToLower(request.Email)))
+	if err != nil {
+		if err != redis.ErrNil {
+			logrus.Errorf("[checkLoginOtp] get account attempts error: %s", err)
+		}
+		return nil
+	}
+
+	if attempts < s.config.LoginProtection.MaxAccountAttempts {
+		return nil
+	}
+
+	if request.OtpRefCode == "" || request.OtpCode == "" {
+		return s.result.LoginOtpRequired
+	}
+
+	return otp.NewService().Verify(ctx, request.Email, request.OtpRefCode, request.OtpCode)
+}
+
+// loginFailed count failed login per account and ip, lock when reached max attempts
+func (s *service) loginFailed(ctx context.Context, email, ip string) error {
+	cf := s.config.LoginProtection
+	client := redis.GetConnection()
+	until := time.Now().Add(cf.LockDuration)
+
+	if cf.MaxIPAttempts > 0 {
+		attempts, err := client.Incr(ctx, fmt.Sprintf(loginAttemptsIPKey, ip), cf.Window)
+		if err != nil {
+			logrus.Errorf("[loginFailed] incr ip attempts error: %s", err)
+		} else if attempts >= cf.MaxIPAttempts {
+			_ = client.Set(ctx, fmt.Sprintf(loginLockIPKey, ip), until, cf.LockDuration)
+			_ = client.Delete(ctx, fmt.Sprintf(loginAttemptsIPKey, ip))
+			logrus.Warnf("[loginFailed] ip %s locked until %s", ip, until)
+			return newAccountLockedError(s.result, until)
+		}
+	}
+
+	if cf.MaxAccountAttempts > 0 {
+		email = strings.ToLower(email)
+		attempts, err := client.Incr(ctx, fmt.Sprintf(loginAttemptsAccountKey, email), cf.Window)
+		if err != nil {
+			logrus.Errorf("[loginFailed] incr account attempts error: %s", err)
+		} else if attempts >= cf.MaxAccountAttempts && cf.Action != LoginProtectionOTP {
+			_ = client.Set(ctx, fmt.Sprintf(loginLockAccountKey, email), until, cf.LockDuration)
+			_ = client.Delete(ctx, fmt.Sprintf(loginAttemptsAccountKey, email))
+			logrus.Warnf("[loginFailed] account %s locked until %s", email, until)
+			return newAccountLockedError(s.result, until)
+		}
+	}
+
+	return s.result.InvalidPassword
+}
+
+// loginSucceeded reset failed attempts of account
+func (s *service) loginSucceeded(ctx context.Context, email string) {
+	_ = redis.GetConnection().Delete(ctx, fmt.Sprintf(loginAttemptsAccountKey, strings.ToLower(email)))
+}
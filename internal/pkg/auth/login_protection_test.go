@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/redis"
+	"github.com/Thospol/go-fiber/internal/core/redis/redistest"
+)
+
+func startTestRedis(t *testing.T) *redistest.Server {
+	t.Helper()
+
+	server := redistest.New(t)
+	host, port := server.HostPort()
+	if err := redis.Init(redis.Configuration{Host: host, Port: port}); err != nil {
+		t.Fatalf("init redis: %s", err)
+	}
+	t.Cleanup(redis.GetConnection().Close)
+
+	return server
+}
+
+func TestLoginOtpRequiredAfterMaxAccountAttempts(t *testing.T) {
+	server := startTestRedis(t)
+
+	cf := &config.Configs{}
+	cf.LoginProtection.Action = LoginProtectionOTP
+	cf.LoginProtection.MaxAccountAttempts = 2
+	cf.LoginProtection.Window = time.Minute
+	cf.LoginProtection.LockDuration = time.Minute
+
+	rr := &config.ReturnResult{}
+	rr.InvalidPassword = config.Result{Code: 400}
+	rr.LoginOtpRequired = config.Result{Code: 428}
+
+	s := &service{config: cf, result: rr}
+	ctx := context.Background()
+	request := &loginRequest{Email: "User@Example.com", IP: "127.0.0.1"}
+
+	for i := 0; i < cf.LoginProtection.MaxAccountAttempts; i++ {
+		if err := s.checkLoginOtp(ctx, request); err != nil {
+			t.Fatalf("attempt %d should not require otp, got %v", i+1, err)
+		}
+
+		if err := s.loginFailed(ctx, request.Email, request.IP); err != rr.InvalidPassword {
+			t.Fatalf("attempt %d should fail with invalid password, got %v", i+1, err)
+		}
+	}
+
+	if err := s.checkLoginOtp(ctx, request); err != rr.LoginOtpRequired {
+		t.Fatalf("expected otp required after max attempts, got %v", err)
+	}
+
+	// failed attempts are counted in window only
+	server.FastForward(cf.LoginProtection.Window)
+	if err := s.checkLoginOtp(ctx, request); err != nil {
+		t.Fatalf("expected no otp after window, got %v", err)
+	}
+
+	for i := 0; i < cf.LoginProtection.MaxAccountAttempts; i++ {
+		_ = s.loginFailed(ctx, request.Email, request.IP)
+	}
+
+	s.loginSucceeded(ctx, request.Email)
+	if err := s.checkLoginOtp(ctx, request); err != nil {
+		t.Fatalf("expected no otp after login succeeded, got %v", err)
+	}
+}
+
+func TestLoginLockedUntilLockDuration(t *testing.T) {
+	server := startTestRedis(t)
+
+	cf := &config.Configs{}
+	cf.LoginProtection.Action = LoginProtectionLock
+	cf.LoginProtection.MaxAccountAttempts = 2
+	cf.LoginProtection.Window = time.Minute
+	cf.LoginProtection.LockDuration = 10 * time.Minute
+
+	rr := &config.ReturnResult{}
+	rr.InvalidPassword = config.Result{Code: 400}
+	rr.AccountTemporarilyLocked = config.Result{Code: 429}
+
+	s := &service{config: cf, result: rr}
+	ctx := context.Background()
+	email := "User@Example.com"
+
+	if err := s.loginFailed(ctx, email, "127.0.0.1"); err != rr.InvalidPassword {
+		t.Fatalf("expected invalid password, got %v", err)
+	}
+
+	if ttl := server.TTL("login_attempts_account:user@example.com"); ttl != cf.LoginProtection.Window {
+		t.Fatalf("expected attempts to expire after window, got %s", ttl)
+	}
+
+	if _, ok := s.loginFailed(ctx, email, "127.0.0.1").(*accountLockedError); !ok {
+		t.Fatalf("expected account locked after max attempts")
+	}
+
+	if ttl := server.TTL("login_lock_account:user@example.com"); ttl != cf.LoginProtection.LockDuration {
+		t.Fatalf("expected lock to expire after lock duration, got %s", ttl)
+	}
+
+	if _, ok := s.checkLoginLocked(ctx, email, "127.0.0.1").(*accountLockedError); !ok {
+		t.Fatalf("expected login locked")
+	}
+
+	server.FastForward(cf.LoginProtection.LockDuration)
+	if server.Exists("login_lock_account:user@example.com") {
+		t.Fatalf("expected lock to expire")
+	}
+}
@@ -0,0 +1,46 @@
+package auth
+
+type registerRequest struct {
+	Pronoun         string `json:"pronoun" validate:"maxString=50"`
+	Name            string `json:"name" validate:"required,maxString=255"`
+	Email           string `json:"email" validate:"required"`
+	PhoneNumber     string `json:"phoneNumber" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	ConfirmPassword string `json:"confirmPassword" validate:"required"`
+}
+
+type loginRequest struct {
+	Email      string `json:"email" validate:"required,email"`
+	Password   string `json:"password" validate:"required"`
+	OtpRefCode string `json:"otpRefCode"`
+	OtpCode    string `json:"otpCode"`
+	UserAgent  string `json:"-"`
+	IP         string `json:"-"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+	UserAgent    string `json:"-"`
+	IP           string `json:"-"`
+}
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type verifyTwoFactorRequest struct {
+	TwoFactorToken string `json:"twoFactorToken" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+	UserAgent      string `json:"-"`
+	IP             string `json:"-"`
+}
+
+type revokeSessionRequest struct {
+	Id string `form:"id" json:"id" path:"id" query:"id" xml:"id"`
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required"`
+	ConfirmPassword string `json:"confirmPassword" validate:"required"`
+}
@@ -0,0 +1,365 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/jwt"
+	"github.com/Thospol/go-fiber/internal/core/password"
+	"github.com/Thospol/go-fiber/internal/core/redis"
+	"github.com/Thospol/go-fiber/internal/core/session"
+	"github.com/Thospol/go-fiber/internal/models"
+	"github.com/Thospol/go-fiber/internal/pkg/account"
+	"github.com/Thospol/go-fiber/internal/pkg/user"
+	"github.com/Thospol/go-fiber/internal/repositories"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Service auth service interface
+type Service interface {
+	Register(database *gorm.DB, request *registerRequest, lang string) (*models.User, error)
+	Login(database *gorm.DB, request *loginRequest) (*models.Token, error)
+	Refresh(database *gorm.DB, request *refreshRequest) (*models.Token, error)
+	Logout(ctx context.Context, user *models.UserSession) error
+	ListSessions(ctx context.Context, user *models.UserSession) ([]*models.DeviceSession, error)
+	RevokeSession(ctx context.Context, user *models.UserSession, request *revokeSessionRequest) error
+	RevokeAllSessions(ctx context.Context, user *models.UserSession) error
+	ChangePassword(database *gorm.DB, user *models.UserSession, request *changePasswordRequest) error
+	EnrollTwoFactor(database *gorm.DB, user *models.UserSession) (*enrollTwoFactorResponse, error)
+	ActivateTwoFactor(database *gorm.DB, user *models.UserSession, request *twoFactorCodeRequest) (*recoveryCodesResponse, error)
+	DisableTwoFactor(database *gorm.DB, user *models.UserSession, request *twoFactorCodeRequest) error
+	VerifyTwoFactor(database *gorm.DB, request *verifyTwoFactorRequest) (*models.Token, error)
+}
+
+type service struct {
+	config     *config.Configs
+	result     *config.ReturnResult
+	repository repositories.Repository
+	account    account.Service
+}
+
+// NewService new auth service
+func NewService() Service {
+	return &service{
+		config:     config.CF,
+		result:     config.RR,
+		repository: repositories.NewRepository(),
+		account:    account.NewService(),
+	}
+}
+
+// Register register user with email, phone number and password, then send verify email
+func (s *service) Register(database *gorm.DB, request *registerRequest, lang string) (*models.User, error) {
+	err := user.CheckContact(database, request.Email, request.PhoneNumber, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	err = password.Validate(request.Password, request.ConfirmPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := password.Hash(request.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := &models.User{
+		Pronoun:     request.Pronoun,
+		Name:        request.Name,
+		Role:        models.RoleUser,
+		Email:       request.Email,
+		PhoneNumber: request.PhoneNumber,
+		Password:    hash,
+	}
+
+	err = s.repository.Create(database, entity)
+	if err != nil {
+		return nil, user.ContactError(err)
+	}
+
+	if err := s.account.SendVerifyEmail(database.Statement.Context, entity, lang); err != nil {
+		logrus.Errorf("[Register] send verify email error: %s", err)
+	}
+
+	return entity, nil
+}
+
+// Login verify credentials and issue token pair
+func (s *service) Login(database *gorm.DB, request *loginRequest) (*models.Token, error) {
+	ctx := database.Statement.Context
+	if err := s.checkLoginLocked(ctx, request.Email, request.IP); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLoginOtp(ctx, request); err != nil {
+		return nil, err
+	}
+
+	user := &models.User{}
+	err := database.Where("email = ?", request.Email).First(user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, s.loginFailed(ctx, request.Email, request.IP)
+		}
+		logrus.Errorf("[Login] find user error: %s", err)
+		return nil, err
+	}
+
+	ok, needsRehash, err := password.Verify(request.Password, user.Password)
+	if err != nil {
+		logrus.Errorf("[Login] verify password error: %s", err)
+		return nil, s.loginFailed(ctx, request.Email, request.IP)
+	}
+
+	if !ok {
+		return nil, s.loginFailed(ctx, request.Email, request.IP)
+	}
+
+	if needsRehash {
+		s.rehashPassword(database, user, request.Password)
+	}
+
+	// failed attempts are reset after second factor
+	if user.TwoFactorEnabled {
+		return s.createTwoFactorToken(ctx, user)
+	}
+
+	s.loginSucceeded(ctx, request.Email)
+
+	return s.createToken(ctx, user, &models.DeviceSession{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		UserAgent: request.UserAgent,
+		IP:        request.IP,
+		CreatedAt: time.Now(),
+	})
+}
+
+// Refresh rotate refresh token and issue new token pair
+func (s *service) Refresh(database *gorm.DB, request *refreshRequest) (*models.Token, error) {
+	claims, err := jwt.Parsed(request.RefreshToken, true)
+	if err != nil {
+		return nil, s.result.InvalidToken
+	}
+
+	if tokenType, _ := claims["type"].(string); tokenType != jwt.RefreshToken {
+		return nil, s.result.InvalidToken
+	}
+
+	sub, _ := claims["sub"].(float64)
+	sessionID, _ := claims["session_id"].(string)
+	refreshUUID, _ := claims["refresh_uuid"].(string)
+
+	ctx := database.Statement.Context
+	ds, err := session.Get(ctx, sessionID)
+	if err != nil || ds.UserID != uint(sub) {
+		return nil, s.result.InvalidToken
+	}
+
+	// refresh token was already rotated, revoke the device session because it may be stolen
+	if ds.RefreshUUID != refreshUUID {
+		logrus.Warnf("[Refresh] refresh token reused on session: %s", ds.ID)
+		_ = session.Revoke(ctx, ds.UserID, ds.ID)
+		return nil, s.result.InvalidToken
+	}
+
+	user := &models.User{}
+	err = database.First(user, ds.UserID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, s.result.InvalidToken
+		}
+		logrus.Errorf("[Refresh] find user error: %s", err)
+		return nil, err
+	}
+
+	// refresh uuid is consumed atomically, so only one of concurrent refreshes with same token succeeds
+	client := redis.GetConnection()
+	var userID uint
+	if err := client.GetDelete(ctx, refreshUUID, &userID); err != nil {
+		if err != redis.ErrNil {
+			return nil, err
+		}
+		logrus.Warnf("[Refresh] refresh token reused on session: %s", ds.ID)
+		_ = session.Revoke(ctx, ds.UserID, ds.ID)
+		return nil, s.result.InvalidToken
+	}
+
+	if err := client.Delete(ctx, ds.AccessUUID); err != nil {
+		return nil, err
+	}
+
+	ds.UserAgent = request.UserAgent
+	ds.IP = request.IP
+	return s.createToken(ctx, user, ds)
+}
+
+// Logout revoke device session of user session
+func (s *service) Logout(ctx context.Context, user *models.UserSession) error {
+	err := session.Revoke(ctx, user.Id, user.SessionID)
+	if err != nil && err != session.ErrorNotFound {
+		return err
+	}
+
+	return s.revokeToken(ctx, user.AccessUUID, user.RefreshUUID)
+}
+
+// ListSessions list device sessions of user
+func (s *service) ListSessions(ctx context.Context, user *models.UserSession) ([]*models.DeviceSession, error) {
+	sessions, err := session.List(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ds := range sessions {
+		ds.Current = ds.ID == user.SessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revoke device session of user
+func (s *service) RevokeSession(ctx context.Context, user *models.UserSession, request *revokeSessionRequest) error {
+	err := session.Revoke(ctx, user.Id, request.Id)
+	if err != nil {
+		if err == session.ErrorNotFound {
+			return s.result.Internal.DatabaseNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAllSessions revoke all device sessions of user (log out everywhere)
+func (s *service) RevokeAllSessions(ctx context.Context, user *models.UserSession) error {
+	return session.RevokeAll(ctx, user.Id)
+}
+
+// ChangePassword change password and revoke all device sessions of user
+func (s *service) ChangePassword(database *gorm.DB, user *models.UserSession, request *changePasswordRequest) error {
+	err := password.Validate(request.NewPassword, request.ConfirmPassword)
+	if err != nil {
+		return err
+	}
+
+	entity := &models.User{}
+	err = database.First(entity, user.Id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.result.Internal.DatabaseNotFound
+		}
+		return err
+	}
+
+	ok, _, err := password.Verify(request.CurrentPassword, entity.Password)
+	if err != nil || !ok {
+		return s.result.InvalidPassword
+	}
+
+	hash, err := password.Hash(request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	err = database.Model(entity).Updates(map[string]interface{}{
+		"password": hash,
+		"version":  gorm.Expr("version + 1"),
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	return session.RevokeAll(database.Statement.Context, user.Id)
+}
+
+// rehashPassword upgrade password hash to current algorithm and parameters
+func (s *service) rehashPassword(database *gorm.DB, user *models.User, plain string) {
+	hash, err := password.Hash(plain)
+	if err != nil {
+		logrus.Errorf("[rehashPassword] hash password error: %s", err)
+		return
+	}
+
+	err = database.Model(user).Updates(map[string]interface{}{
+		"password": hash,
+		"version":  gorm.Expr("version + 1"),
+	}).Error
+	if err != nil {
+		logrus.Errorf("[rehashPassword] update password error: %s", err)
+	}
+}
+
+// createToken sign token pair, store both uuids on redis and save device session
+func (s *service) createToken(ctx context.Context, user *models.User, ds *models.DeviceSession) (*models.Token, error) {
+	now := time.Now()
+	accessUUID := uuid.New().String()
+	refreshUUID := uuid.New().String()
+	accessExpire := s.config.JWT.Access.Duration()
+	refreshExpire := s.config.JWT.Refresh.Duration()
+
+	accessToken, err := jwt.Signed(map[string]interface{}{
+		"sub":          user.ID,
+		"type":         jwt.AccessToken,
+		"session_id":   ds.ID,
+		"access_uuid":  accessUUID,
+		"refresh_uuid": refreshUUID,
+		"role":         user.Role,
+		"permissions":  s.config.RolePermissions(user.Role),
+		"two_factor":   ds.TwoFactor,
+	}, now.Add(accessExpire))
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := jwt.Signed(map[string]interface{}{
+		"sub":          user.ID,
+		"type":         jwt.RefreshToken,
+		"session_id":   ds.ID,
+		"access_uuid":  accessUUID,
+		"refresh_uuid": refreshUUID,
+	}, now.Add(refreshExpire))
+	if err != nil {
+		return nil, err
+	}
+
+	client := redis.GetConnection()
+	if err := client.Set(ctx, accessUUID, user.ID, accessExpire); err != nil {
+		return nil, err
+	}
+
+	if err := client.Set(ctx, refreshUUID, user.ID, refreshExpire); err != nil {
+		return nil, err
+	}
+
+	ds.AccessUUID = accessUUID
+	ds.RefreshUUID = refreshUUID
+	ds.LastSeenAt = now
+	ds.ExpiresAt = now.Add(refreshExpire)
+	if err := session.Save(ctx, ds); err != nil {
+		return nil, err
+	}
+
+	return &models.Token{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessExpire.Seconds()),
+	}, nil
+}
+
+// revokeToken delete token uuids from redis
+func (s *service) revokeToken(ctx context.Context, accessUUID, refreshUUID string) error {
+	client := redis.GetConnection()
+	if err := client.Delete(ctx, accessUUID); err != nil {
+		return err
+	}
+
+	return client.Delete(ctx, refreshUUID)
+}
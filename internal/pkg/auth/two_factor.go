@@ -0,0 +1,349 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/jwt"
+	"github.com/Thospol/go-fiber/internal/core/redis"
+	"github.com/Thospol/go-fiber/internal/core/sql"
+	"github.com/Thospol/go-fiber/internal/core/totp"
+	"github.com/Thospol/go-fiber/internal/core/utils"
+	"github.com/Thospol/go-fiber/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	twoFactorKey         = "two_factor:%s"
+	twoFactorAttemptsKey = "two_factor_attempts:%d"
+	twoFactorLockKey     = "two_factor_lock:%d"
+	twoFactorEnrollKey   = "two_factor_enroll:%d"
+	totpUsedKey          = "totp_used:%d:%d"
+
+	twoFactorEnrollExpire = 10 * time.Minute
+	totpSkew              = 1
+	recoveryCodeAmount    = 10
+	recoveryCodeCharset   = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// EnrollTwoFactor generate pending totp secret and provisioning uri
+func (s *service) EnrollTwoFactor(database *gorm.DB, user *models.UserSession) (*enrollTwoFactorResponse, error) {
+	entity, err := s.findUser(database, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.TwoFactorEnabled {
+		return nil, s.result.Internal.BadRequest
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	err = redis.GetConnection().Set(database.Statement.Context, fmt.Sprintf(twoFactorEnrollKey, entity.ID), secret, twoFactorEnrollExpire)
+	if err != nil {
+		return nil, err
+	}
+
+	return &enrollTwoFactorResponse{
+		Secret: secret,
+		URI:    totp.ProvisioningURI(s.config.TwoFactor.Issuer, entity.Email, secret),
+	}, nil
+}
+
+// ActivateTwoFactor verify code of pending secret, enable two factor and generate recovery codes
+func (s *service) ActivateTwoFactor(database *gorm.DB, user *models.UserSession, request *twoFactorCodeRequest) (*recoveryCodesResponse, error) {
+	ctx := database.Statement.Context
+	if err := s.checkTwoFactorLocked(ctx, user.Id); err != nil {
+		return nil, err
+	}
+
+	client := redis.GetConnection()
+	var secret string
+	if err := client.Get(ctx, fmt.Sprintf(twoFactorEnrollKey, user.Id), &secret); err != nil {
+		return nil, s.result.OtpInvalidOrExpired
+	}
+
+	if _, ok := totp.Validate(secret, request.Code, time.Now(), totpSkew); !ok {
+		return nil, s.twoFactorFailed(ctx, user.Id)
+	}
+	s.twoFactorSucceeded(ctx, user.Id)
+
+	codes := []string{}
+	err := sql.WithinTransactionOn(database, func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", user.Id).Updates(map[string]interface{}{
+			"two_factor_enabled": true,
+			"two_factor_secret":  secret,
+			"version":            gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		codes, err = s.generateRecoveryCodes(tx, user.Id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_ = client.Delete(ctx, fmt.Sprintf(twoFactorEnrollKey, user.Id))
+	return &recoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor disable two factor with totp or recovery code
+func (s *service) DisableTwoFactor(database *gorm.DB, user *models.UserSession, request *twoFactorCodeRequest) error {
+	entity, err := s.findUser(database, user.Id)
+	if err != nil {
+		return err
+	}
+
+	if !entity.TwoFactorEnabled {
+		return nil
+	}
+
+	if err := s.checkTwoFactorLocked(database.Statement.Context, entity.ID); err != nil {
+		return err
+	}
+
+	ok, err := s.verifyTwoFactorCode(database, entity, request.Code)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return s.twoFactorFailed(database.Statement.Context, entity.ID)
+	}
+	s.twoFactorSucceeded(database.Statement.Context, entity.ID)
+
+	return sql.WithinTransactionOn(database, func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", entity.ID).Updates(map[string]interface{}{
+			"two_factor_enabled": false,
+			"two_factor_secret":  "",
+			"version":            gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", entity.ID).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+// VerifyTwoFactor second login step, issue token pair after valid totp or recovery code,
+// failed attempts are counted per user so new login does not reset them
+func (s *service) VerifyTwoFactor(database *gorm.DB, request *verifyTwoFactorRequest) (*models.Token, error) {
+	claims, err := jwt.Parsed(request.TwoFactorToken, true)
+	if err != nil {
+		return nil, s.result.InvalidToken
+	}
+
+	if tokenType, _ := claims["type"].(string); tokenType != jwt.TwoFactorToken {
+		return nil, s.result.InvalidToken
+	}
+
+	sub, _ := claims["sub"].(float64)
+	jti, _ := claims["jti"].(string)
+
+	ctx := database.Statement.Context
+	client := redis.GetConnection()
+	var userID uint
+	if err := client.Get(ctx, fmt.Sprintf(twoFactorKey, jti), &userID); err != nil || userID != uint(sub) {
+		return nil, s.result.InvalidToken
+	}
+
+	if err := s.checkTwoFactorLocked(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.findUser(database, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.verifyTwoFactorCode(database, user, request.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		err = s.twoFactorFailed(ctx, userID)
+		if _, locked := err.(*accountLockedError); locked {
+			_ = client.Delete(ctx, fmt.Sprintf(twoFactorKey, jti))
+		}
+		return nil, err
+	}
+
+	_ = client.Delete(ctx, fmt.Sprintf(twoFactorKey, jti))
+	s.twoFactorSucceeded(ctx, userID)
+	s.loginSucceeded(ctx, user.Email)
+
+	return s.createToken(ctx, user, &models.DeviceSession{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		UserAgent: request.UserAgent,
+		IP:        request.IP,
+		CreatedAt: time.Now(),
+		TwoFactor: true,
+	})
+}
+
+// createTwoFactorToken create single use token for second login step
+func (s *service) createTwoFactorToken(ctx context.Context, user *models.User) (*models.Token, error) {
+	jti := uuid.New().String()
+	token, err := jwt.Signed(map[string]interface{}{
+		"sub":  user.ID,
+		"type": jwt.TwoFactorToken,
+		"jti":  jti,
+	}, time.Now().Add(s.config.TwoFactor.ExpireTime))
+	if err != nil {
+		return nil, err
+	}
+
+	err = redis.GetConnection().Set(ctx, fmt.Sprintf(twoFactorKey, jti), user.ID, s.config.TwoFactor.ExpireTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Token{
+		TwoFactorRequired: true,
+		TwoFactorToken:    token,
+	}, nil
+}
+
+// checkTwoFactorLocked check two factor of user is not locked
+func (s *service) checkTwoFactorLocked(ctx context.Context, userID uint) error {
+	var until time.Time
+	err := redis.GetConnection().Get(ctx, fmt.Sprintf(twoFactorLockKey, userID), &until)
+	if err == nil && time.Now().Before(until) {
+		return newAccountLockedError(s.result, until)
+	}
+
+	return nil
+}
+
+// twoFactorFailed count failed code of user, lock two factor of user when reached max attempts
+func (s *service) twoFactorFailed(ctx context.Context, userID uint) error {
+	lockDuration := s.config.TwoFactor.LockDuration
+	if lockDuration <= 0 {
+		lockDuration = s.config.TwoFactor.ExpireTime
+	}
+
+	client := redis.GetConnection()
+	attempts, err := client.Incr(ctx, fmt.Sprintf(twoFactorAttemptsKey, userID), lockDuration)
+	if err != nil {
+		logrus.Errorf("[twoFactorFailed] incr attempts error: %s", err)
+		return s.result.OtpInvalidOrExpired
+	}
+
+	if attempts >= s.config.TwoFactor.MaxAttempts {
+		until := time.Now().Add(lockDuration)
+		_ = client.Set(ctx, fmt.Sprintf(twoFactorLockKey, userID), until, lockDuration)
+		_ = client.Delete(ctx, fmt.Sprintf(twoFactorAttemptsKey, userID))
+		logrus.Warnf("[twoFactorFailed] two factor of user %d locked until %s", userID, until)
+		return newAccountLockedError(s.result, until)
+	}
+
+	return s.result.OtpInvalidOrExpired
+}
+
+// twoFactorSucceeded reset failed attempts of user
+func (s *service) twoFactorSucceeded(ctx context.Context, userID uint) {
+	_ = redis.GetConnection().Delete(ctx, fmt.Sprintf(twoFactorAttemptsKey, userID))
+}
+
+// verifyTwoFactorCode verify totp code (each time step is usable once) or unused recovery code
+func (s *service) verifyTwoFactorCode(database *gorm.DB, user *models.User, code string) (bool, error) {
+	if counter, ok := totp.Validate(user.TwoFactorSecret, code, time.Now(), totpSkew); ok {
+		used, err := redis.GetConnection().Incr(database.Statement.Context, fmt.Sprintf(totpUsedKey, user.ID, counter), 2*(totpSkew+1)*totp.Period*time.Second)
+		if err != nil {
+			return false, err
+		}
+
+		return used == 1, nil
+	}
+
+	result := database.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, utils.SHA256HashHex(code)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// generateRecoveryCodes replace recovery codes of user, only hashes are stored
+func (s *service) generateRecoveryCodes(database *gorm.DB, userID uint) ([]string, error) {
+	err := database.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeAmount)
+	entities := make([]*models.RecoveryCode, 0, recoveryCodeAmount)
+	for i := 0; i < recoveryCodeAmount; i++ {
+		code, err := randomRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+		entity := &models.RecoveryCode{
+			UserID:   userID,
+			CodeHash: utils.SHA256HashHex(code),
+		}
+		entity.Stamp()
+		entities = append(entities, entity)
+	}
+
+	err = s.repository.BulkInsert(database, entities)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// findUser find user by id
+func (s *service) findUser(database *gorm.DB, userID uint) (*models.User, error) {
+	user := &models.User{}
+	err := s.repository.FindByID(database, userID, user)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, s.result.Internal.DatabaseNotFound
+		}
+		logrus.Errorf("[findUser] find user error: %s", err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func randomRecoveryCode() (string, error) {
+	b := make([]byte, 9)
+	max := big.NewInt(int64(len(recoveryCodeCharset)))
+	for i := range b {
+		if i == 4 {
+			b[i] = '-'
+			continue
+		}
+
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = recoveryCodeCharset[n.Int64()]
+	}
+
+	return string(b), nil
+}
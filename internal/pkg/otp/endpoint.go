@@ -0,0 +1,93 @@
+package otp
+
+import (
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/context"
+	"github.com/Thospol/go-fiber/internal/core/render"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Endpoint otp endpoint interface
+type Endpoint interface {
+	Request(c *fiber.Ctx) error
+	Verify(c *fiber.Ctx) error
+}
+
+type endpoint struct {
+	config  *config.Configs
+	result  *config.ReturnResult
+	service Service
+}
+
+// NewEndpoint new otp endpoint
+func NewEndpoint() Endpoint {
+	return &endpoint{
+		config:  config.CF,
+		result:  config.RR,
+		service: NewService(),
+	}
+}
+
+// Request godoc
+// @Tags OTP
+// @Summary Request
+// @Description Request send otp to phone number or email
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body requestOTPRequest true "request body"
+// @Success 200 {object} Reference
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 429 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Router /otp/request [post]
+func (ep *endpoint) Request(c *fiber.Ctx) error {
+	request := new(requestOTPRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[Request] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	lang, _ := c.Locals(context.LangKey).(string)
+	response, err := ep.service.Send(ctx.RequestContext(), request.Channel, request.Recipient, lang)
+	if err != nil {
+		logrus.Errorf("[Request] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// Verify godoc
+// @Tags OTP
+// @Summary Verify
+// @Description Request verify otp by reference code
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body verifyOTPRequest true "request body"
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Router /otp/verify [post]
+func (ep *endpoint) Verify(c *fiber.Ctx) error {
+	request := new(verifyOTPRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[Verify] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	err = ep.service.Verify(ctx.RequestContext(), request.Recipient, request.RefCode, request.Code)
+	if err != nil {
+		logrus.Errorf("[Verify] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
@@ -0,0 +1,12 @@
+package otp
+
+type requestOTPRequest struct {
+	Channel   string `json:"channel" validate:"required,oneof=sms email"`
+	Recipient string `json:"recipient" validate:"required"`
+}
+
+type verifyOTPRequest struct {
+	Recipient string `json:"recipient" validate:"required"`
+	RefCode   string `json:"refCode" validate:"required"`
+	Code      string `json:"code" validate:"required"`
+}
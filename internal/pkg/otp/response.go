@@ -0,0 +1,9 @@
+package otp
+
+import "time"
+
+// Reference otp reference for verify
+type Reference struct {
+	RefCode   string    `json:"refCode"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,187 @@
+package otp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/notification"
+	"github.com/Thospol/go-fiber/internal/core/redis"
+	"github.com/Thospol/go-fiber/internal/core/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SMS channel sms
+	SMS = "sms"
+	// Email channel email
+	Email = "email"
+
+	otpKey         = "otp:%s"
+	otpAttemptsKey = "otp_attempts:%s"
+	otpCooldownKey = "otp_cooldown:%s"
+
+	refCodeLength  = 6
+	refCodeCharset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// entry otp stored on redis
+type entry struct {
+	Recipient string
+	Hash      string
+}
+
+// Service otp service interface
+type Service interface {
+	Send(ctx context.Context, channel, recipient, lang string) (*Reference, error)
+	Verify(ctx context.Context, recipient, refCode, code string) error
+}
+
+type service struct {
+	config  *config.Configs
+	result  *config.ReturnResult
+	senders map[string]notification.Sender
+}
+
+// NewService new otp service
+func NewService() Service {
+	return &service{
+		config: config.CF,
+		result: config.RR,
+		senders: map[string]notification.Sender{
+			SMS:   notification.NewSMSSender(config.CF.Notification.SMS),
+			Email: notification.NewEmailSender(config.CF.Notification.Email),
+		},
+	}
+}
+
+// Send generate otp, store hashed otp on redis and send to recipient
+func (s *service) Send(ctx context.Context, channel, recipient, lang string) (*Reference, error) {
+	sender, ok := s.senders[channel]
+	if !ok {
+		return nil, s.result.Internal.BadRequest
+	}
+
+	if channel == Email && !utils.IsValidEmail(recipient) {
+		return nil, s.result.InvalidEmail
+	}
+
+	if channel == SMS && !utils.IsValidPhoneNumber(recipient) {
+		return nil, s.result.InvalidPhoneNumber
+	}
+
+	client := redis.GetConnection()
+	requests, err := client.Incr(ctx, fmt.Sprintf(otpCooldownKey, recipient), s.config.OTP.RequestInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	if requests > 1 {
+		return nil, s.result.Internal.TooManyRequests
+	}
+
+	code, err := randomString("0123456789", s.config.OTP.Length)
+	if err != nil {
+		return nil, err
+	}
+
+	refCode, err := randomString(refCodeCharset, refCodeLength)
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.Set(ctx, fmt.Sprintf(otpKey, refCode), &entry{
+		Recipient: recipient,
+		Hash:      s.hash(refCode, code),
+	}, s.config.OTP.ExpireTime)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sender.Send(recipient, s.message(code, refCode, lang))
+	if err != nil {
+		logrus.Errorf("[Send] send otp error: %s", err)
+		_ = client.Delete(ctx, fmt.Sprintf(otpKey, refCode))
+		return nil, err
+	}
+
+	return &Reference{
+		RefCode:   refCode,
+		ExpiresAt: time.Now().Add(s.config.OTP.ExpireTime),
+	}, nil
+}
+
+// Verify verify otp, otp is single use and invalidated after max attempts
+func (s *service) Verify(ctx context.Context, recipient, refCode, code string) error {
+	client := redis.GetConnection()
+	attempts, err := client.Incr(ctx, fmt.Sprintf(otpAttemptsKey, refCode), s.config.OTP.ExpireTime)
+	if err != nil {
+		return err
+	}
+
+	if attempts > s.config.OTP.MaxAttempts {
+		_ = client.Delete(ctx, fmt.Sprintf(otpKey, refCode))
+		return s.result.OtpInvalidOrExpired
+	}
+
+	otp := &entry{}
+	if err := client.Get(ctx, fmt.Sprintf(otpKey, refCode), otp); err != nil {
+		return s.result.OtpInvalidOrExpired
+	}
+
+	if otp.Recipient != recipient ||
+		subtle.ConstantTimeCompare([]byte(otp.Hash), []byte(s.hash(refCode, code))) != 1 {
+		return s.result.OtpInvalidOrExpired
+	}
+
+	// otp is consumed atomically, so only one of concurrent correct submissions is accepted
+	deleted, err := client.CompareAndDelete(ctx, fmt.Sprintf(otpKey, refCode), otp)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return s.result.OtpInvalidOrExpired
+	}
+
+	_ = client.Delete(ctx, fmt.Sprintf(otpAttemptsKey, refCode))
+	return nil
+}
+
+func (s *service) hash(refCode, code string) string {
+	return utils.SHA256HashHex(fmt.Sprintf("%s:%s:%s", s.config.JWT.SecretKey, refCode, code))
+}
+
+func (s *service) message(code, refCode, lang string) notification.Message {
+	minutes := int(s.config.OTP.ExpireTime.Minutes())
+	if lang == "th" {
+		return notification.Message{
+			Subject: "รหัสยืนยัน (OTP)",
+			Body:    fmt.Sprintf("รหัสยืนยันของคุณคือ %s (รหัสอ้างอิง: %s) มีอายุการใช้งาน %d นาที", code, refCode, minutes),
+		}
+	}
+
+	return notification.Message{
+		Subject: "Verification code (OTP)",
+		Body:    fmt.Sprintf("Your verification code is %s (ref: %s). It expires in %d minutes", code, refCode, minutes),
+	}
+}
+
+func randomString(charset string, length int) (string, error) {
+	b := make([]byte, length)
+	max := big.NewInt(int64(len(charset)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = charset[n.Int64()]
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,45 @@
+package system
+
+import (
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/render"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Endpoint system endpoint interface
+type Endpoint interface {
+	PoolStats(c *fiber.Ctx) error
+}
+
+type endpoint struct {
+	config  *config.Configs
+	result  *config.ReturnResult
+	service Service
+}
+
+// NewEndpoint new system endpoint
+func NewEndpoint() Endpoint {
+	return &endpoint{
+		config:  config.CF,
+		result:  config.RR,
+		service: NewService(),
+	}
+}
+
+// PoolStats godoc
+// @Tags System
+// @Summary PoolStats
+// @Description Request connection pool stats of sql (primary and replicas), mongo and redis, max open 0 is driver default (unlimited for sql and redis)
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Success 200 {object} poolStatsResponse
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 403 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Security ServiceKeyAuth
+// @Router /system/pool-stats [get]
+func (ep *endpoint) PoolStats(c *fiber.Ctx) error {
+	return render.JSON(c, ep.service.PoolStats())
+}
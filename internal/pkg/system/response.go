@@ -0,0 +1,19 @@
+package system
+
+type poolStatsResponse struct {
+	Pools []*poolStats `json:"pools"`
+}
+
+type poolStats struct {
+	Name              string `json:"name"`
+	MaxOpen           int    `json:"maxOpen"`
+	Open              int    `json:"open"`
+	InUse             int    `json:"inUse"`
+	Idle              int    `json:"idle"`
+	WaitCount         int64  `json:"waitCount"`
+	WaitDurationMs    int64  `json:"waitDurationMs"`
+	MaxIdleClosed     int64  `json:"maxIdleClosed"`
+	MaxIdleTimeClosed int64  `json:"maxIdleTimeClosed"`
+	MaxLifetimeClosed int64  `json:"maxLifetimeClosed"`
+	CheckOutFailed    int64  `json:"checkOutFailed"`
+}
@@ -0,0 +1,68 @@
+package system
+
+import (
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/mongodb"
+	"github.com/Thospol/go-fiber/internal/core/redis"
+	"github.com/Thospol/go-fiber/internal/core/sql"
+)
+
+// Service system service interface
+type Service interface {
+	PoolStats() *poolStatsResponse
+}
+
+type service struct {
+	config *config.Configs
+	result *config.ReturnResult
+}
+
+// NewService new system service
+func NewService() Service {
+	return &service{
+		config: config.CF,
+		result: config.RR,
+	}
+}
+
+// PoolStats stats of connection pools of opened databases
+func (s *service) PoolStats() *poolStatsResponse {
+	response := &poolStatsResponse{Pools: []*poolStats{}}
+	for _, stats := range sql.Stats() {
+		response.Pools = append(response.Pools, &poolStats{
+			Name:              stats.Name,
+			MaxOpen:           stats.MaxOpenConnections,
+			Open:              stats.OpenConnections,
+			InUse:             stats.InUse,
+			Idle:              stats.Idle,
+			WaitCount:         stats.WaitCount,
+			WaitDurationMs:    stats.WaitDuration.Milliseconds(),
+			MaxIdleClosed:     stats.MaxIdleClosed,
+			MaxIdleTimeClosed: stats.MaxIdleTimeClosed,
+			MaxLifetimeClosed: stats.MaxLifetimeClosed,
+		})
+	}
+
+	for _, stats := range mongodb.Stats() {
+		response.Pools = append(response.Pools, &poolStats{
+			Name:           stats.Name,
+			MaxOpen:        int(stats.MaxPoolSize),
+			Open:           int(stats.Open),
+			InUse:          int(stats.InUse),
+			Idle:           int(stats.Open - stats.InUse),
+			CheckOutFailed: stats.CheckOutFailed,
+		})
+	}
+
+	for _, stats := range redis.Stats() {
+		response.Pools = append(response.Pools, &poolStats{
+			Name:    stats.Name,
+			MaxOpen: stats.MaxActive,
+			Open:    stats.ActiveCount,
+			InUse:   stats.ActiveCount - stats.IdleCount,
+			Idle:    stats.IdleCount,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,65 @@
+package user
+
+import (
+	"strings"
+
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/sql"
+	"github.com/Thospol/go-fiber/internal/core/utils"
+	"github.com/Thospol/go-fiber/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CheckContact validate email and phone number, check both are not used by other user than userId
+func CheckContact(database *gorm.DB, email, phoneNumber string, userId uint) error {
+	if !utils.IsValidEmail(email) {
+		return config.RR.InvalidEmail
+	}
+
+	if !utils.IsValidPhoneNumber(phoneNumber) {
+		return config.RR.InvalidPhoneNumber
+	}
+
+	var count int64
+	err := database.Model(&models.User{}).
+		Where("email = ? AND id <> ?", email, userId).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return config.RR.EmailAlreadyExists
+	}
+
+	err = database.Model(&models.User{}).
+		Where("phone_number = ? AND id <> ?", phoneNumber, userId).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return config.RR.PhoneNumberAlreadyExists
+	}
+
+	return nil
+}
+
+// ContactError map unique violation of concurrent insert or update of user to already exists result
+func ContactError(err error) error {
+	if !sql.IsUniqueViolation(err) {
+		return err
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "phone_number"):
+		return config.RR.PhoneNumberAlreadyExists
+	case strings.Contains(msg, "email"):
+		return config.RR.EmailAlreadyExists
+	}
+
+	return config.RR.Internal.Conflict
+}
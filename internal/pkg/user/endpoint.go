@@ -3,6 +3,7 @@ package user
 import (
 	"github.com/Thospol/go-fiber/internal/core/config"
 	"github.com/Thospol/go-fiber/internal/core/context"
+	"github.com/Thospol/go-fiber/internal/core/query"
 	"github.com/Thospol/go-fiber/internal/core/render"
 
 	"github.com/gofiber/fiber/v2"
@@ -11,7 +12,11 @@ import (
 
 // Endpoint user endpoint interface
 type Endpoint interface {
+	CreateUser(c *fiber.Ctx) error
+	ListUsers(c *fiber.Ctx) error
 	GetUser(c *fiber.Ctx) error
+	UpdateUser(c *fiber.Ctx) error
+	DeleteUser(c *fiber.Ctx) error
 }
 
 type endpoint struct {
@@ -28,6 +33,79 @@ func NewEndpoint() Endpoint {
 	}
 }
 
+// CreateUser godoc
+// @Tags User
+// @Summary CreateUser
+// @Description Request create user
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param request body createUserRequest true "request body"
+// @Success 200 {object} models.User
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 403 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Security ServiceKeyAuth
+// @Router /users [post]
+func (ep *endpoint) CreateUser(c *fiber.Ctx) error {
+	request := new(createUserRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[CreateUser] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	response, err := ep.service.CreateUser(ctx.GetPostgreDatabase(), request)
+	if err != nil {
+		logrus.Errorf("[CreateUser] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// ListUsers godoc
+// @Tags User
+// @Summary ListUsers
+// @Description Request list users, sort by `id, createdAt, updatedAt, name, email` and filter by `id, createdAt, updatedAt, name, role, email, phoneNumber`
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param page query int false "page" default(1)
+// @Param limit query int false "limit" default(20)
+// @Param sort query string false "sort e.g. -createdAt,name"
+// @Param filter query string false "filter e.g. role:in:admin|user,name:like:tho, escape , : | with \\"
+// @Param pagination query string false "(offset, cursor)" default(offset)
+// @Param cursor query string false "next or prev cursor from previous response"
+// @Success 200 {object} query.Result
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 403 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Security ServiceKeyAuth
+// @Router /users [get]
+func (ep *endpoint) ListUsers(c *fiber.Ctx) error {
+	request := new(query.Query)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, false)
+	if err != nil {
+		logrus.Errorf("[ListUsers] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	response, err := ep.service.ListUsers(ctx.GetPostgreDatabase(), request)
+	if err != nil {
+		logrus.Errorf("[ListUsers] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
 // GetUser godoc
 // @Tags User
 // @Summary GetUser
@@ -38,8 +116,12 @@ func NewEndpoint() Endpoint {
 // @Param id path string true "input id" default(1)
 // @Success 200 {object} models.User
 // @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 403 {object} config.SwaggerInfoResult
+// @Failure 404 {object} config.SwaggerInfoResult
 // @Failure 500 {object} config.SwaggerInfoResult
 // @Security ApiKeyAuth
+// @Security ServiceKeyAuth
 // @Router /users/{id} [get]
 func (ep *endpoint) GetUser(c *fiber.Ctx) error {
 	request := new(getUserRequest)
@@ -58,3 +140,79 @@ func (ep *endpoint) GetUser(c *fiber.Ctx) error {
 
 	return render.JSON(c, response)
 }
+
+// UpdateUser godoc
+// @Tags User
+// @Summary UpdateUser
+// @Description Request update user by id, version from `If-Match` header or body is required and checked against current version
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param If-Match header string false "ETag of user from previous response"
+// @Param id path string true "input id" default(1)
+// @Param request body updateUserRequest true "request body"
+// @Success 200 {object} models.User
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 403 {object} config.SwaggerInfoResult
+// @Failure 404 {object} config.SwaggerInfoResult
+// @Failure 409 {object} config.SwaggerInfoResult
+// @Failure 428 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Security ServiceKeyAuth
+// @Router /users/{id} [put]
+func (ep *endpoint) UpdateUser(c *fiber.Ctx) error {
+	request := new(updateUserRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, true)
+	if err != nil {
+		logrus.Errorf("[UpdateUser] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	// current version is read from primary, replicas may lag behind
+	ctx.ForcePrimary()
+	response, err := ep.service.UpdateUser(ctx.GetPostgreDatabase(), request)
+	if err != nil {
+		logrus.Errorf("[UpdateUser] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, response)
+}
+
+// DeleteUser godoc
+// @Tags User
+// @Summary DeleteUser
+// @Description Request delete user by id, all device sessions of user are revoked
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "(en, th)" default(th)
+// @Param id path string true "input id" default(1)
+// @Success 200 {object} config.SwaggerInfoResult
+// @Failure 400 {object} config.SwaggerInfoResult
+// @Failure 401 {object} config.SwaggerInfoResult
+// @Failure 403 {object} config.SwaggerInfoResult
+// @Failure 404 {object} config.SwaggerInfoResult
+// @Failure 500 {object} config.SwaggerInfoResult
+// @Security ApiKeyAuth
+// @Security ServiceKeyAuth
+// @Router /users/{id} [delete]
+func (ep *endpoint) DeleteUser(c *fiber.Ctx) error {
+	request := new(deleteUserRequest)
+	ctx := context.New(c)
+	err := ctx.BindValue(request, false)
+	if err != nil {
+		logrus.Errorf("[DeleteUser] bind value error: %s", err)
+		return render.Error(c, err)
+	}
+
+	err = ep.service.DeleteUser(ctx.GetPostgreDatabase(), request.Id)
+	if err != nil {
+		logrus.Errorf("[DeleteUser] call service error: %s", err)
+		return render.Error(c, err)
+	}
+
+	return render.JSON(c, ep.result.Internal.Success.WithLocale(c))
+}
@@ -1,5 +1,30 @@
 package user
 
+import "github.com/Thospol/go-fiber/internal/models"
+
 type getUserRequest struct {
 	Id uint `form:"id" json:"id" path:"id" query:"id" xml:"id"`
 }
+
+type createUserRequest struct {
+	Pronoun         string `json:"pronoun" validate:"maxString=50"`
+	Name            string `json:"name" validate:"required,maxString=255"`
+	Role            string `json:"role" validate:"omitempty,oneof=admin user"`
+	Email           string `json:"email" validate:"required"`
+	PhoneNumber     string `json:"phoneNumber" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	ConfirmPassword string `json:"confirmPassword" validate:"required"`
+}
+
+type updateUserRequest struct {
+	models.Versioned
+	Id          uint   `form:"id" json:"-" path:"id" query:"id" xml:"id"`
+	Pronoun     string `json:"pronoun" validate:"maxString=50"`
+	Name        string `json:"name" validate:"required,maxString=255"`
+	Role        string `json:"role" validate:"omitempty,oneof=admin user"`
+	PhoneNumber string `json:"phoneNumber" validate:"required"`
+}
+
+type deleteUserRequest struct {
+	Id uint `form:"id" json:"id" path:"id" query:"id" xml:"id"`
+}
@@ -1,41 +1,182 @@
 package user
 
 import (
-	"time"
+	"errors"
 
 	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/outbox"
+	"github.com/Thospol/go-fiber/internal/core/password"
+	"github.com/Thospol/go-fiber/internal/core/query"
+	"github.com/Thospol/go-fiber/internal/core/session"
 	"github.com/Thospol/go-fiber/internal/models"
+	"github.com/Thospol/go-fiber/internal/repositories"
 
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 // Service user service interface
 type Service interface {
+	CreateUser(database *gorm.DB, request *createUserRequest) (*models.User, error)
+	ListUsers(database *gorm.DB, q *query.Query) (*query.Result, error)
 	GetUser(database *gorm.DB, userId uint) (*models.User, error)
+	UpdateUser(database *gorm.DB, request *updateUserRequest) (*models.User, error)
+	DeleteUser(database *gorm.DB, userId uint) error
 }
 
 type service struct {
-	config *config.Configs
-	result *config.ReturnResult
+	config     *config.Configs
+	result     *config.ReturnResult
+	repository repositories.Repository
 }
 
 // NewService new user service
 func NewService() Service {
 	return &service{
-		config: config.CF,
-		result: config.RR,
+		config:     config.CF,
+		result:     config.RR,
+		repository: repositories.NewRepository(),
 	}
 }
 
+// CreateUser create user
+func (s *service) CreateUser(database *gorm.DB, request *createUserRequest) (*models.User, error) {
+	err := CheckContact(database, request.Email, request.PhoneNumber, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	err = password.Validate(request.Password, request.ConfirmPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := password.Hash(request.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Pronoun:     request.Pronoun,
+		Name:        request.Name,
+		Role:        request.Role,
+		Email:       request.Email,
+		PhoneNumber: request.PhoneNumber,
+		Password:    hash,
+	}
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+
+	err = s.repository.Create(database, user)
+	if err != nil {
+		return nil, ContactError(err)
+	}
+
+	err = outbox.Publish(database, "user.created", user)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ListUsers list users with pagination, sort and filter
+func (s *service) ListUsers(database *gorm.DB, q *query.Query) (*query.Result, error) {
+	users := []*models.User{}
+	if q.IsCursor() {
+		cursor, err := s.repository.FindAllByCursor(database, q, &users)
+		if err != nil {
+			return nil, err
+		}
+
+		return &query.Result{Data: users, Cursor: cursor}, nil
+	}
+
+	meta, err := s.repository.FindAll(database, q, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	return &query.Result{Data: users, Meta: meta}, nil
+}
+
 // GetUser get user
 func (s *service) GetUser(database *gorm.DB, userId uint) (*models.User, error) {
-	return &models.User{
-		Model: models.Model{
-			ID:        userId,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
-		Pronoun: "Mr.",
-		Name:    "Thosapol",
-	}, nil
+	user := &models.User{}
+	err := s.repository.FindByID(database, userId, user)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, s.result.Internal.DatabaseNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateUser update user, version of request is required so update is never last write wins,
+// all device sessions are revoked when role is changed
+func (s *service) UpdateUser(database *gorm.DB, request *updateUserRequest) (*models.User, error) {
+	if request.Version == 0 {
+		return nil, s.result.Internal.PreconditionRequired
+	}
+
+	user, err := s.GetUser(database, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = CheckContact(database, user.Email, request.PhoneNumber, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	roleChanged := request.Role != "" && request.Role != user.Role
+	user.Version = request.Version
+	user.Pronoun = request.Pronoun
+	user.Name = request.Name
+	user.PhoneNumber = request.PhoneNumber
+	if request.Role != "" {
+		user.Role = request.Role
+	}
+
+	err = s.repository.Update(database, user)
+	if err != nil {
+		return nil, ContactError(err)
+	}
+
+	// permissions of role are signed into access token, so sessions of old role must not be used anymore
+	if roleChanged {
+		if err := session.RevokeAll(database.Statement.Context, user.ID); err != nil {
+			logrus.Errorf("[UpdateUser] revoke sessions error: %s", err)
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// DeleteUser delete user and revoke all device sessions
+func (s *service) DeleteUser(database *gorm.DB, userId uint) error {
+	user, err := s.GetUser(database, userId)
+	if err != nil {
+		return err
+	}
+
+	err = s.repository.Delete(database, user)
+	if err != nil {
+		return err
+	}
+
+	err = outbox.Publish(database, "user.deleted", user)
+	if err != nil {
+		return err
+	}
+
+	if err := session.RevokeAll(database.Statement.Context, user.ID); err != nil {
+		logrus.Errorf("[DeleteUser] revoke sessions error: %s", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/Thospol/go-fiber/internal/core/audit"
+	"github.com/Thospol/go-fiber/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// audit write audit record of change through database of change, actor and request id are taken from context of database
+func (repo *repository) audit(database *gorm.DB, action string, before, after interface{}) error {
+	if !audit.Enabled() {
+		return nil
+	}
+
+	entity := after
+	if entity == nil {
+		entity = before
+	}
+
+	var id string
+	if m, ok := entity.(models.ModelInterface); ok {
+		id = strconv.FormatUint(uint64(m.GetID()), 10)
+	}
+
+	table := ""
+	stmt := &gorm.Statement{DB: database}
+	if err := stmt.Parse(entity); err == nil {
+		table = stmt.Schema.Table
+	}
+
+	return audit.WriteTx(database, action, table, id, before, after)
+}
+
+// findBefore find current record of entity for audit
+func (repo *repository) findBefore(database *gorm.DB, i interface{}) interface{} {
+	m, ok := i.(models.ModelInterface)
+	if !audit.Enabled() || !ok {
+		return nil
+	}
+
+	before := reflect.New(reflect.TypeOf(i).Elem()).Interface()
+	if err := database.Session(&gorm.Session{NewDB: true}).First(before, m.GetID()).Error; err != nil {
+		return nil
+	}
+
+	return before
+}
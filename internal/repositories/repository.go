@@ -1,18 +1,26 @@
 package repositories
 
 import (
+	"reflect"
+
+	"github.com/Thospol/go-fiber/internal/core/audit"
+	"github.com/Thospol/go-fiber/internal/core/config"
+	"github.com/Thospol/go-fiber/internal/core/query"
 	"github.com/Thospol/go-fiber/internal/models"
 
 	"gorm.io/gorm"
 )
 
-// Repository repository interface
+// Repository repository interface, database carries context of request (ctx.GetPostgreDatabase),
+// so operations are cancelled at deadline of request
 type Repository interface {
 	Create(database *gorm.DB, i interface{}) error
 	Update(database *gorm.DB, i interface{}) error
 	Delete(database *gorm.DB, i interface{}) error
 	FindByID(database *gorm.DB, id uint, i interface{}) error
 	BulkInsert(database *gorm.DB, sliceValue interface{}) error
+	FindAll(database *gorm.DB, q *query.Query, sliceValue interface{}) (*query.Meta, error)
+	FindAllByCursor(database *gorm.DB, q *query.Query, sliceValue interface{}) (*query.CursorMeta, error)
 }
 
 type repository struct{}
@@ -28,19 +36,49 @@ func (repo *repository) Create(database *gorm.DB, i interface{}) error {
 		m.Stamp()
 	}
 
+	if v, ok := i.(models.VersionInterface); ok && v.GetVersion() == 0 {
+		v.SetVersion(1)
+	}
+
 	if err := database.Create(i).Error; err != nil {
 		return err
 	}
 
-	return nil
+	return repo.audit(database, audit.Create, nil, i)
 }
 
-// Update update record database
+// Update update record database, versioned model is updated only when version is not changed
 func (repo *repository) Update(database *gorm.DB, i interface{}) error {
+	before := repo.findBefore(database, i)
+	if err := repo.update(database, i); err != nil {
+		return err
+	}
+
+	return repo.audit(database, audit.Update, before, i)
+}
+
+func (repo *repository) update(database *gorm.DB, i interface{}) error {
 	if m, ok := i.(models.ModelInterface); ok {
 		m.UpdateStamp()
 	}
 
+	if v, ok := i.(models.VersionInterface); ok {
+		version := v.GetVersion()
+		v.SetVersion(version + 1)
+		result := database.Where("version = ?", version).Select("*").Save(i)
+		if result.Error != nil {
+			v.SetVersion(version)
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			v.SetVersion(version)
+			return config.RR.Internal.Conflict
+		}
+
+		return nil
+	}
+
 	if err := database.Save(i).Error; err != nil {
 		return err
 	}
@@ -58,7 +96,7 @@ func (repo *repository) Delete(database *gorm.DB, i interface{}) error {
 		return err
 	}
 
-	return nil
+	return repo.audit(database, audit.Delete, i, nil)
 }
 
 // FindByID find by id record database
@@ -76,5 +114,52 @@ func (repo *repository) BulkInsert(database *gorm.DB, sliceValue interface{}) er
 		return result.Error
 	}
 
+	if audit.Enabled() {
+		slice := reflect.Indirect(reflect.ValueOf(sliceValue))
+		for j := 0; j < slice.Len(); j++ {
+			if err := repo.audit(database, audit.Create, nil, slice.Index(j).Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
+
+// FindAll find records with filter, sort and pagination of query
+func (repo *repository) FindAll(database *gorm.DB, q *query.Query, sliceValue interface{}) (*query.Meta, error) {
+	filter, sort, err := q.Scopes(sliceValue)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := database.Model(sliceValue).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if err := database.Scopes(filter, sort, q.Paginate).Find(sliceValue).Error; err != nil {
+		return nil, err
+	}
+
+	return q.Meta(total), nil
+}
+
+// FindAllByCursor find records with filter and keyset pagination of query
+func (repo *repository) FindAllByCursor(database *gorm.DB, q *query.Query, sliceValue interface{}) (*query.CursorMeta, error) {
+	filter, _, err := q.Scopes(sliceValue)
+	if err != nil {
+		return nil, err
+	}
+
+	keyset, err := q.Keyset(sliceValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.Scopes(filter, keyset.Scope).Find(sliceValue).Error; err != nil {
+		return nil, err
+	}
+
+	return keyset.Meta(sliceValue)
+}
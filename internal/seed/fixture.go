@@ -0,0 +1,123 @@
+package seed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Thospol/go-fiber/internal/core/utils"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Fixture fixture file, records are inserted into table (sql) or collection (mongo) of database,
+// password of shared environments is read from environment variable by `$password:env:<NAME>`
+//
+//	database: postgres
+//	table: users
+//	records:
+//	  - ref: admin
+//	    data:
+//	      name: Admin
+//	      password: $password:secret
+type Fixture struct {
+	Name     string    `json:"-" yaml:"-"`
+	Checksum string    `json:"-" yaml:"-"`
+	Database string    `json:"database" yaml:"database"`
+	Table    string    `json:"table" yaml:"table"`
+	Records  []*Record `json:"records" yaml:"records"`
+}
+
+// Record fixture record, ref is name for reference from other records by `$ref:<name>`
+type Record struct {
+	Ref  string                 `json:"ref" yaml:"ref"`
+	Data map[string]interface{} `json:"data" yaml:"data"`
+}
+
+// Load load fixtures (.json, .yml, .yaml) of dir sorted by file name
+func Load(dir string) ([]*Fixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yml", ".yaml":
+			if !entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+
+	fixtures := []*Fixture{}
+	for _, name := range names {
+		fixture, err := loadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", name, err)
+		}
+
+		fixture.Name = name
+		fixtures = append(fixtures, fixture)
+	}
+
+	return fixtures, nil
+}
+
+func loadFile(path string) (*Fixture, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := utils.ReadJSONFile(path, fixture); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := yaml.Unmarshal(content, fixture); err != nil {
+			return nil, err
+		}
+
+		for _, record := range fixture.Records {
+			for key, value := range record.Data {
+				record.Data[key] = normalize(value)
+			}
+		}
+	}
+
+	if fixture.Database == "" || fixture.Table == "" {
+		return nil, fmt.Errorf("database and table are required")
+	}
+
+	sum := sha256.Sum256(content)
+	fixture.Checksum = hex.EncodeToString(sum[:])
+
+	return fixture, nil
+}
+
+// normalize convert yaml maps into json compatible maps
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalize(item)
+		}
+		return m
+
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+	}
+
+	return value
+}
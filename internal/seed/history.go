@@ -0,0 +1,89 @@
+package seed
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+)
+
+const historyTable = "seed_history"
+
+// Reference id of seeded record
+type Reference struct {
+	Database string `json:"database" bson:"database"`
+	ID       string `json:"id" bson:"id"`
+}
+
+// References references of fixture by ref name, stored as json on sql
+type References map[string]Reference
+
+// History applied fixture, references are kept for fixtures applied later
+type History struct {
+	Name       string     `json:"name" bson:"_id" gorm:"primary_key"`
+	Checksum   string     `json:"checksum" bson:"checksum"`
+	References References `json:"references" bson:"references"`
+	AppliedAt  time.Time  `json:"appliedAt" bson:"applied_at"`
+}
+
+// TableName table name of history
+func (History) TableName() string {
+	return historyTable
+}
+
+// Value implements driver.Valuer
+func (r References) Value() (driver.Value, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (r *References) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, r)
+	case string:
+		return json.Unmarshal([]byte(v), r)
+	}
+
+	return fmt.Errorf("unsupported references type: %T", src)
+}
+
+// sqlHistories applied fixtures of sql database
+func sqlHistories(database *gorm.DB) ([]*History, error) {
+	histories := []*History{}
+	if err := database.Find(&histories).Error; err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// mongoHistories applied fixtures of mongo database
+func mongoHistories(database *mongo.Database) ([]*History, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.Collection(historyTable).Find(ctx, primitive.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	histories := []*History{}
+	if err := cursor.All(ctx, &histories); err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
@@ -0,0 +1,351 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Thospol/go-fiber/internal/core/mongodb"
+	"github.com/Thospol/go-fiber/internal/core/password"
+	"github.com/Thospol/go-fiber/internal/repositories"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	// Postgres database postgres
+	Postgres = "postgres"
+	// MySQL database mysql
+	MySQL = "mysql"
+	// Mongo database mongo
+	Mongo = "mongo"
+
+	refPrefix      = "$ref:"
+	passwordPrefix = "$password:"
+	envPrefix      = "env:"
+)
+
+// Options databases of seeder, fixtures of nil database cannot be applied
+type Options struct {
+	Postgres *gorm.DB
+	MySQL    *gorm.DB
+	Mongo    *mongo.Database
+}
+
+// Seeder seeder
+type Seeder struct {
+	options    Options
+	repository repositories.Repository
+	schemas    map[string]*schema.Schema
+	histories  map[string]*History
+	references References
+}
+
+// document schemaless document of mongo fixture
+type document struct {
+	mongodb.Model `bson:",inline"`
+	Fields        map[string]interface{} `bson:",inline"`
+}
+
+// New new seeder
+func New(options Options) *Seeder {
+	return &Seeder{
+		options:    options,
+		repository: repositories.NewRepository(),
+		schemas:    map[string]*schema.Schema{},
+		histories:  map[string]*History{},
+		references: References{},
+	}
+}
+
+// Register register models of sql tables, records of sql fixture are decoded into model of table
+func (s *Seeder) Register(models ...interface{}) error {
+	for _, model := range models {
+		sch, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+		if err != nil {
+			return err
+		}
+		s.schemas[sch.Table] = sch
+	}
+
+	return nil
+}
+
+// Run apply fixtures of dir which are not applied yet, returns names of applied fixtures
+func (s *Seeder) Run(dir string) ([]string, error) {
+	fixtures, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadHistories(); err != nil {
+		return nil, err
+	}
+
+	applied := []string{}
+	for _, fixture := range fixtures {
+		if history, ok := s.histories[fixture.Name]; ok {
+			if history.Checksum != fixture.Checksum {
+				logrus.Warnf("[seed] %s is changed after applied, skipped", fixture.Name)
+			}
+			continue
+		}
+
+		logrus.Infof("[seed] apply %s", fixture.Name)
+		if err := s.apply(fixture); err != nil {
+			return applied, fmt.Errorf("fixture %s: %w", fixture.Name, err)
+		}
+		applied = append(applied, fixture.Name)
+	}
+
+	return applied, nil
+}
+
+// loadHistories load applied fixtures and their references of all databases
+func (s *Seeder) loadHistories() error {
+	histories := []*History{}
+	for _, database := range []*gorm.DB{s.options.Postgres, s.options.MySQL} {
+		if database == nil {
+			continue
+		}
+
+		h, err := sqlHistories(database)
+		if err != nil {
+			return err
+		}
+		histories = append(histories, h...)
+	}
+
+	if s.options.Mongo != nil {
+		h, err := mongoHistories(s.options.Mongo)
+		if err != nil {
+			return err
+		}
+		histories = append(histories, h...)
+	}
+
+	for _, history := range histories {
+		s.histories[history.Name] = history
+		for ref, reference := range history.References {
+			s.references[ref] = reference
+		}
+	}
+
+	return nil
+}
+
+// apply insert records of fixture and record history
+func (s *Seeder) apply(fixture *Fixture) error {
+	history := &History{
+		Name:       fixture.Name,
+		Checksum:   fixture.Checksum,
+		References: References{},
+		AppliedAt:  time.Now(),
+	}
+
+	var err error
+	switch fixture.Database {
+	case Postgres:
+		err = s.applySQL(s.options.Postgres, fixture, history)
+	case MySQL:
+		err = s.applySQL(s.options.MySQL, fixture, history)
+	case Mongo:
+		err = s.applyMongo(fixture, history)
+	default:
+		err = fmt.Errorf("unsupported database: %s", fixture.Database)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.histories[history.Name] = history
+	for ref, reference := range history.References {
+		s.references[ref] = reference
+	}
+
+	return nil
+}
+
+// applySQL bulk insert records and history in a transaction
+func (s *Seeder) applySQL(database *gorm.DB, fixture *Fixture, history *History) error {
+	if database == nil {
+		return fmt.Errorf("database %s is not connected", fixture.Database)
+	}
+
+	sch, ok := s.schemas[fixture.Table]
+	if !ok {
+		return fmt.Errorf("model of table %s is not registered", fixture.Table)
+	}
+
+	slice := reflect.MakeSlice(reflect.SliceOf(reflect.PtrTo(sch.ModelType)), 0, len(fixture.Records))
+	for _, record := range fixture.Records {
+		item := reflect.New(sch.ModelType)
+		for key, value := range record.Data {
+			field := sch.LookUpField(key)
+			if field == nil {
+				return fmt.Errorf("unknown field %s of table %s", key, fixture.Table)
+			}
+
+			value, err := s.resolve(value)
+			if err != nil {
+				return err
+			}
+
+			if err := field.Set(database.Statement.Context, item.Elem(), parseTime(field, value)); err != nil {
+				return fmt.Errorf("field %s: %w", key, err)
+			}
+		}
+		slice = reflect.Append(slice, item)
+	}
+
+	return database.Transaction(func(tx *gorm.DB) error {
+		if slice.Len() > 0 {
+			if err := s.repository.BulkInsert(tx, slice.Interface()); err != nil {
+				return err
+			}
+		}
+
+		for i, record := range fixture.Records {
+			if record.Ref == "" {
+				continue
+			}
+
+			id := sch.PrioritizedPrimaryField.ReflectValueOf(tx.Statement.Context, slice.Index(i).Elem()).Interface()
+			if err := s.addReference(history, record.Ref, fixture.Database, fmt.Sprint(id)); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(history).Error
+	})
+}
+
+// applyMongo insert records as schemaless documents and history
+func (s *Seeder) applyMongo(fixture *Fixture, history *History) error {
+	if s.options.Mongo == nil {
+		return fmt.Errorf("database %s is not connected", fixture.Database)
+	}
+
+	documents := make([]*document, 0, len(fixture.Records))
+	for _, record := range fixture.Records {
+		fields, err := s.resolve(record.Data)
+		if err != nil {
+			return err
+		}
+		documents = append(documents, &document{Fields: fields.(map[string]interface{})})
+	}
+
+	if len(documents) > 0 {
+		repo := &mongodb.Repo{Collection: s.options.Mongo.Collection(fixture.Table)}
+		if err := repo.CreateMany(documents); err != nil {
+			return err
+		}
+	}
+
+	for i, record := range fixture.Records {
+		if record.Ref == "" {
+			continue
+		}
+
+		if err := s.addReference(history, record.Ref, fixture.Database, documents[i].GetID().Hex()); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := s.options.Mongo.Collection(historyTable).InsertOne(ctx, history)
+	return err
+}
+
+func (s *Seeder) addReference(history *History, ref, database, id string) error {
+	if _, ok := s.references[ref]; ok {
+		return fmt.Errorf("duplicate ref: %s", ref)
+	}
+
+	if _, ok := history.References[ref]; ok {
+		return fmt.Errorf("duplicate ref: %s", ref)
+	}
+
+	history.References[ref] = Reference{Database: database, ID: id}
+	return nil
+}
+
+// resolve replace `$ref:<name>` with id of referenced record and `$password:<plain>` with hashed password,
+// `$password:env:<NAME>` is hashed password of environment variable, fixture fails when it is not set
+func (s *Seeder) resolve(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, refPrefix) {
+			ref := strings.TrimPrefix(v, refPrefix)
+			reference, ok := s.references[ref]
+			if !ok {
+				return nil, fmt.Errorf("unknown ref: %s", ref)
+			}
+
+			if reference.Database == Mongo {
+				return primitive.ObjectIDFromHex(reference.ID)
+			}
+			return strconv.ParseUint(reference.ID, 10, 64)
+		}
+
+		if strings.HasPrefix(v, passwordPrefix) {
+			plain := strings.TrimPrefix(v, passwordPrefix)
+			if strings.HasPrefix(plain, envPrefix) {
+				name := strings.TrimPrefix(plain, envPrefix)
+				plain = os.Getenv(name)
+				if plain == "" {
+					return nil, fmt.Errorf("environment variable %s of password is not set", name)
+				}
+			}
+			return password.Hash(plain)
+		}
+
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			resolved, err := s.resolve(item)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = resolved
+		}
+		return m, nil
+
+	case []interface{}:
+		a := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			resolved, err := s.resolve(item)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, resolved)
+		}
+		return a, nil
+	}
+
+	return value, nil
+}
+
+// parseTime parse RFC3339 string of time field
+func parseTime(field *schema.Field, value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok || field.IndirectFieldType != reflect.TypeOf(time.Time{}) {
+		return value
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+
+	return value
+}
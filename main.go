@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"os"
+	"os/signal"
 
 	"github.com/Thospol/go-fiber/docs"
+	"github.com/Thospol/go-fiber/internal/core/audit"
 	"github.com/Thospol/go-fiber/internal/core/config"
 	"github.com/Thospol/go-fiber/internal/core/jwt"
 	"github.com/Thospol/go-fiber/internal/core/mongodb"
+	"github.com/Thospol/go-fiber/internal/core/notification"
+	"github.com/Thospol/go-fiber/internal/core/outbox"
 	"github.com/Thospol/go-fiber/internal/core/redis"
 	"github.com/Thospol/go-fiber/internal/core/sql"
 	"github.com/Thospol/go-fiber/internal/handlers/routes"
@@ -19,6 +25,10 @@ import (
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
+
+// @securityDefinitions.apikey ServiceKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
 	environment := flag.String("environment", "local", "set working environment")
 	configs := flag.String("config", "configs", "set configs path, default as: 'configs'")
@@ -57,8 +67,27 @@ func main() {
 	}
 	//=======================================================
 
-	// Get SecretKey JWT
-	jwt.LoadKey()
+	// Run subcommand (migrate, seed) instead of server
+	if args := flag.Args(); len(args) > 0 {
+		if err := runCommand(args); err != nil {
+			logrus.Fatal(err)
+		}
+		return
+	}
+	//=======================================================
+
+	// Check notification drivers
+	err = notification.Validate(config.CF.App.Release, config.CF.Notification.Email, config.CF.Notification.SMS)
+	if err != nil {
+		panic(err)
+	}
+	// =======================================================
+
+	// Load signing keys JWT
+	err = jwt.LoadKey()
+	if err != nil {
+		panic(err)
+	}
 	// =======================================================
 
 	// Init connection postgresql
@@ -81,14 +110,7 @@ func main() {
 
 	// Init connection mongoDB
 	if config.CF.Mongo.Enable {
-		err = mongodb.InitDatabase(&mongodb.Options{
-			URL:          config.CF.Mongo.Host,
-			Port:         config.CF.Mongo.Port,
-			Username:     config.CF.Mongo.Username,
-			Password:     config.CF.Mongo.Password,
-			DatabaseName: config.CF.Mongo.DatabaseName,
-			Debug:        !config.CF.App.Release,
-		})
+		err = mongodb.InitDatabase(mongoOptions(config.CF.Mongo))
 		if err != nil {
 			panic(err)
 		}
@@ -97,18 +119,125 @@ func main() {
 
 	// Init connection redis
 	if config.CF.Redis.Enable {
-		conf := redis.Configuration{
-			Host:     config.CF.Redis.Host,
-			Port:     config.CF.Redis.Port,
-			Password: config.CF.Redis.Password,
+		if err := redis.Init(redisConfiguration(config.CF.Redis)); err != nil {
+			panic(err)
+		}
+	}
+	//========================================================
+
+	// Init named connections
+	for name, conf := range config.CF.Databases.SQL {
+		if !conf.Enable {
+			continue
+		}
+		if _, err := sql.Open(name, conf); err != nil {
+			panic(err)
+		}
+	}
+
+	for name, conf := range config.CF.Databases.Mongo {
+		if !conf.Enable {
+			continue
 		}
-		if err := redis.Init(conf); err != nil {
+		if _, err := mongodb.Connect(name, mongoOptions(conf)); err != nil {
 			panic(err)
 		}
 	}
+
+	for name, conf := range config.CF.Databases.Redis {
+		if !conf.Enable {
+			continue
+		}
+		if _, err := redis.Open(name, redisConfiguration(conf)); err != nil {
+			panic(err)
+		}
+	}
+	//========================================================
+
+	// Init audit store
+	if config.CF.Audit.Enable {
+		switch config.CF.Audit.Store {
+		case "sql":
+			audit.SetStore(audit.NewSQLStore(sql.PostgreDatabase))
+		case "mongo":
+			audit.SetStore(audit.NewMongoStore(mongodb.DB().Collection("audit_records"), config.CF.Mongo.QueryTimeout))
+		default:
+			audit.SetStore(audit.NewLogStore())
+		}
+	}
+	//========================================================
+
+	// Start outbox relay, relay is stopped on shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var relayDone chan struct{}
+	if config.CF.Outbox.Enable {
+		var publisher outbox.Publisher
+		switch config.CF.Outbox.Publisher {
+		case "redis":
+			publisher = outbox.NewRedisPublisher(redis.GetConnection())
+		case "webhook":
+			publisher = outbox.NewWebhookPublisher(config.CF.Outbox.Webhook.URL, config.CF.Outbox.Webhook.Secret)
+		default:
+			publisher = outbox.NewLogPublisher()
+		}
+
+		relay := outbox.NewRelay(sql.PostgreDatabase, publisher, outbox.Options{
+			Interval:    config.CF.Outbox.Interval,
+			BatchSize:   config.CF.Outbox.BatchSize,
+			MaxAttempts: config.CF.Outbox.MaxAttempts,
+			Backoff:     config.CF.Outbox.Backoff,
+			Lease:       config.CF.Outbox.Lease,
+		})
+		relayDone = make(chan struct{})
+		go func() {
+			defer close(relayDone)
+			relay.Start(ctx)
+		}()
+	}
 	//========================================================
 
 	// New router
 	routes.NewRouter()
 	//========================================================
+
+	// Wait outbox relay to stop
+	stop()
+	if relayDone != nil {
+		<-relayDone
+	}
+	//========================================================
+}
+
+// mongoOptions mongo options of database config
+func mongoOptions(conf config.DatabaseConfig) *mongodb.Options {
+	return &mongodb.Options{
+		URL:              conf.Host,
+		Port:             conf.Port,
+		Username:         conf.Username,
+		Password:         conf.Password,
+		DatabaseName:     conf.DatabaseName,
+		MaxPoolSize:      uint64(conf.Pool.MaxOpen),
+		MinPoolSize:      uint64(conf.Pool.MaxIdle),
+		MaxConnIdleTime:  conf.Pool.MaxIdleTime,
+		Timeout:          conf.Timeout,
+		QueryTimeout:     conf.QueryTimeout,
+		AggregateTimeout: conf.AggregateTimeout,
+		Debug:            !config.CF.App.Release,
+	}
+}
+
+// redisConfiguration redis configuration of database config
+func redisConfiguration(conf config.DatabaseConfig) redis.Configuration {
+	return redis.Configuration{
+		Host:            conf.Host,
+		Port:            conf.Port,
+		Password:        conf.Password,
+		MaxIdle:         conf.Pool.MaxIdle,
+		MaxActive:       conf.Pool.MaxOpen,
+		IdleTimeout:     conf.Pool.MaxIdleTime,
+		MaxConnLifetime: conf.Pool.MaxLifetime,
+		Timeout:         conf.Timeout,
+		QueryTimeout:    conf.QueryTimeout,
+	}
 }